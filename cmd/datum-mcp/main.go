@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 
+	"github.com/datum-cloud/datum-mcp/internal/api"
+	"github.com/datum-cloud/datum-mcp/internal/auth"
 	"github.com/datum-cloud/datum-mcp/internal/server"
 	"github.com/spf13/cobra"
 )
@@ -13,18 +16,31 @@ func newRootCmd() *cobra.Command {
 	var mode string
 	var host string
 	var port int
+	var loginMode string
+	var staticToolsOnly bool
+	var groupSuffixFrom string
+	var groupSuffixTo string
 
 	cmd := &cobra.Command{
 		Use:   "datum-mcp",
 		Short: "Datum MCP server",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := auth.SetLoginMode(loginMode); err != nil {
+				return err
+			}
+			if (groupSuffixFrom == "") != (groupSuffixTo == "") {
+				return fmt.Errorf("--group-suffix-from and --group-suffix-to must be set together")
+			}
+			if groupSuffixFrom != "" {
+				api.SetMiddleware(api.GroupSuffixMiddleware{From: groupSuffixFrom, To: groupSuffixTo})
+			}
 			ctx := context.Background()
 			switch mode {
 			case "stdio":
-				return server.Run(ctx)
+				return server.Run(ctx, staticToolsOnly)
 			case "http":
 				addr := fmt.Sprintf("%s:%d", host, port)
-				return server.RunHTTP(ctx, addr)
+				return server.RunHTTP(ctx, addr, staticToolsOnly)
 			default:
 				return fmt.Errorf("unknown mode: %s", mode)
 			}
@@ -34,6 +50,10 @@ func newRootCmd() *cobra.Command {
 	cmd.Flags().StringVar(&mode, "mode", "stdio", "transport mode: stdio | http")
 	cmd.Flags().StringVar(&host, "host", "localhost", "http host")
 	cmd.Flags().IntVar(&port, "port", 8000, "http port")
+	cmd.Flags().StringVar(&loginMode, "login-mode", "auto", "login flow: browser | device | auto (auto picks device when no browser can be opened)")
+	cmd.Flags().BoolVar(&staticToolsOnly, "static-tools-only", false, "only serve the hand-written tools; skip discovery-driven per-CRD tool registration")
+	cmd.Flags().StringVar(&groupSuffixFrom, "group-suffix-from", os.Getenv("DATUM_GROUP_SUFFIX_FROM"), "caller-facing API group suffix to rewrite outbound (e.g. \"datum.net\"); requires --group-suffix-to")
+	cmd.Flags().StringVar(&groupSuffixTo, "group-suffix-to", os.Getenv("DATUM_GROUP_SUFFIX_TO"), "API group suffix the target control plane actually serves (e.g. \"datum-staging.net\"); requires --group-suffix-from")
 
 	return cmd
 }
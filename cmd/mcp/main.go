@@ -1,35 +1,37 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 
-	"github.com/datum-cloud/datum-mcp/internal/kube"
+	"github.com/datum-cloud/datum-mcp/internal/discovery"
 	"github.com/datum-cloud/datum-mcp/internal/mcp"
 )
 
 func main() {
 	var (
-		port     int
-		context  string
-		ns       string
-		kubepath string
+		sseAddr        string
+		legacyREST     bool
+		legacyRESTPort int
 	)
-	flag.IntVar(&port, "port", 0, "Run HTTP server for manual testing on this port")
-	flag.StringVar(&context, "kube-context", "", "kubectl --context to use")
-	flag.StringVar(&ns, "namespace", "", "Default namespace (-n) for validation")
-	flag.StringVar(&kubepath, "kubectl", "kubectl", "Path to kubectl binary")
+	flag.StringVar(&sseAddr, "sse-addr", "", "Also serve the Streamable-HTTP/SSE MCP transport (e.g. :8443) for remote clients")
+	flag.BoolVar(&legacyREST, "legacy-rest", false, "Also serve the pre-MCP /datum/* REST endpoints, for callers not yet migrated to a real MCP client")
+	flag.IntVar(&legacyRESTPort, "legacy-rest-port", 8089, "Port for --legacy-rest")
 	flag.Parse()
 
-	k := kube.New()
-	k.Path = kubepath
-	k.Context = context
-	k.Namespace = ns
+	disc := discovery.New()
+	if err := disc.Refresh(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "[datum-mcp] initial discovery refresh failed, continuing with an empty cache: %v\n", err)
+	}
 
-	svc := mcp.NewService(k)
-	// Run the MCP JSON-RPC bridge over STDIO; optional HTTP if --port > 0.
-	svc.RunSTDIO(port)
+	svc := mcp.NewService(disc)
+	mcp.Serve(svc, mcp.ServeOptions{
+		StreamableAddr: sseAddr,
+		LegacyREST:     legacyREST,
+		LegacyRESTPort: legacyRESTPort,
+	})
 
 	fmt.Fprintf(os.Stderr, "[datum-mcp] exiting\n")
 }
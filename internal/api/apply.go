@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DryRunApply mirrors kubectl apply's create-then-update fallback: it
+// creates the object if it doesn't exist yet, or merges in's spec onto the
+// live object and updates it otherwise. Passing dryRun requests
+// ?dryRun=All so nothing is persisted; fieldValidation=Strict is always
+// requested so unknown/duplicate fields come back as server errors instead
+// of being silently dropped.
+func DryRunApply(ctx context.Context, cli ctrlclient.Client, group, kind, namespace, name string, in any, fieldManager string, dryRun bool) (*unstructured.Unstructured, error) {
+	objGVK, _, err := resolveGVKs(group, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj unstructured.Unstructured
+	obj.SetGroupVersionKind(objGVK)
+	if err := assignJSON(&obj.Object, in); err != nil {
+		return nil, err
+	}
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+
+	var dr []string
+	if dryRun {
+		dr = []string{metav1.DryRunAll}
+	}
+
+	var existing unstructured.Unstructured
+	existing.SetGroupVersionKind(objGVK)
+	getErr := cli.Get(ctx, ctrlclient.ObjectKey{Namespace: namespace, Name: name}, &existing)
+	switch {
+	case apierrors.IsNotFound(getErr):
+		err = cli.Create(ctx, &obj, &ctrlclient.CreateOptions{
+			DryRun:       dr,
+			FieldManager: fieldManager,
+			Raw:          &metav1.CreateOptions{FieldValidation: "Strict"},
+		})
+	case getErr != nil:
+		return nil, getErr
+	default:
+		if spec, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec"); found {
+			if err := unstructured.SetNestedField(existing.Object, spec, "spec"); err != nil {
+				return nil, fmt.Errorf("merge spec onto existing object: %w", err)
+			}
+		}
+		obj = existing
+		err = cli.Update(ctx, &obj, &ctrlclient.UpdateOptions{
+			DryRun:       dr,
+			FieldManager: fieldManager,
+			Raw:          &metav1.UpdateOptions{FieldValidation: "Strict"},
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	obj.SetGroupVersionKind(inboundGVK(obj.GroupVersionKind()))
+	return &obj, nil
+}
+
+// StatusErrorDetails extracts the structured metav1.Status (causes, field
+// paths) from a server-side apply/create/update error, for callers that want
+// to surface the API server's validation failure as JSON rather than a flat
+// error string.
+func StatusErrorDetails(err error) (metav1.Status, bool) {
+	var se *apierrors.StatusError
+	if !errors.As(err, &se) {
+		return metav1.Status{}, false
+	}
+	return se.Status(), true
+}
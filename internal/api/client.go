@@ -14,12 +14,15 @@ import (
 )
 
 // resolveGVKs resolves the preferred object and list GVKs for a given Group/Kind
-// using the shared RESTMapper. It returns an error if mapping cannot be resolved.
+// using the shared RESTMapper. The group is first translated through any
+// installed ClientMiddleware so callers can keep using the caller-facing API
+// group even when the target control plane serves a renamed one.
 func resolveGVKs(group, kind string) (schema.GroupVersionKind, schema.GroupVersionKind, error) {
 	if sharedMapper == nil {
 		return schema.GroupVersionKind{}, schema.GroupVersionKind{}, fmt.Errorf("rest mapper not initialized")
 	}
-	m, err := sharedMapper.RESTMapping(schema.GroupKind{Group: group, Kind: kind})
+	wire := outboundGVK(schema.GroupVersionKind{Group: group, Kind: kind})
+	m, err := sharedMapper.RESTMapping(schema.GroupKind{Group: wire.Group, Kind: wire.Kind})
 	if err != nil || m == nil {
 		return schema.GroupVersionKind{}, schema.GroupVersionKind{}, fmt.Errorf("failed to resolve GVK for %s/%s: %w", group, kind, err)
 	}
@@ -39,6 +42,7 @@ func FetchObject(ctx context.Context, cli ctrlclient.Client, group, kind, namesp
 	if err := cli.Get(ctx, ctrlclient.ObjectKey{Namespace: namespace, Name: name}, &obj); err != nil {
 		return nil, err
 	}
+	obj.SetGroupVersionKind(inboundGVK(obj.GroupVersionKind()))
 	return &obj, nil
 }
 
@@ -58,6 +62,10 @@ func FetchList(ctx context.Context, cli ctrlclient.Client, group, kind, namespac
 			return nil, err
 		}
 	}
+	list.SetGroupVersionKind(inboundGVK(list.GroupVersionKind()))
+	for i := range list.Items {
+		list.Items[i].SetGroupVersionKind(inboundGVK(list.Items[i].GroupVersionKind()))
+	}
 	return &list, nil
 }
 
@@ -77,6 +85,7 @@ func CreateObject(ctx context.Context, cli ctrlclient.Client, group, kind, names
 	if err := cli.Create(ctx, &obj); err != nil {
 		return nil, err
 	}
+	obj.SetGroupVersionKind(inboundGVK(obj.GroupVersionKind()))
 	return &obj, nil
 }
 
@@ -100,6 +109,7 @@ func UpdateObjectSpec(ctx context.Context, cli ctrlclient.Client, group, kind, n
 	if err := cli.Update(ctx, &obj); err != nil {
 		return nil, err
 	}
+	obj.SetGroupVersionKind(inboundGVK(obj.GroupVersionKind()))
 	return &obj, nil
 }
 
@@ -118,7 +128,9 @@ func DeleteObject(ctx context.Context, cli ctrlclient.Client, group, kind, names
 }
 
 // Discovery: CRD schema via OpenAPI v3 direct path: /openapi/v3/apis/<group>/<version>[/<kind>]
-func GetResourceDefinition(ctx context.Context, project, group, version, kind string, out any) error {
+// When trim is true, the returned schema(s) are reduced via TrimSchema into
+// a JSON-schema-lite view instead of the raw OpenAPI component.
+func GetResourceDefinition(ctx context.Context, project, group, version, kind string, trim bool, out any) error {
 	httpClient, host, err := NewProjectHTTPClient(ctx, project)
 	if err != nil {
 		return err
@@ -137,7 +149,8 @@ func GetResourceDefinition(ctx context.Context, project, group, version, kind st
 	if err := json.NewDecoder(idxResp.Body).Decode(&idx); err != nil {
 		return err
 	}
-	target := "apis/" + strings.Trim(group, ".") + "/" + strings.Trim(version, ".")
+	wireGVK := outboundGVK(schema.GroupVersionKind{Group: group, Version: version})
+	target := "apis/" + strings.Trim(wireGVK.Group, ".") + "/" + strings.Trim(wireGVK.Version, ".")
 	rel := ""
 	if m, ok := idx.(map[string]any); ok {
 		if r, ok := getIndexComponentRel(m, target); ok {
@@ -165,7 +178,11 @@ func GetResourceDefinition(ctx context.Context, project, group, version, kind st
 							gStr, _ := em["group"].(string)
 							vStr, _ := em["version"].(string)
 							kStr, _ := em["kind"].(string)
-							if strings.EqualFold(gStr, strings.Trim(group, ".")) && strings.EqualFold(vStr, strings.Trim(version, ".")) && strings.EqualFold(kStr, k) {
+							if strings.EqualFold(gStr, strings.Trim(wireGVK.Group, ".")) && strings.EqualFold(vStr, strings.Trim(wireGVK.Version, ".")) && strings.EqualFold(kStr, k) {
+								rewriteXGVKInbound(sm)
+								if trim {
+									return assignJSON(out, TrimSchema(m, sm))
+								}
 								return assignJSON(out, sm)
 							}
 						}
@@ -174,9 +191,44 @@ func GetResourceDefinition(ctx context.Context, project, group, version, kind st
 			}
 		}
 	}
+	if m, ok := doc.(map[string]any); ok {
+		if comps, ok := m["components"].(map[string]any); ok {
+			if schemas, ok := comps["schemas"].(map[string]any); ok {
+				for name, v := range schemas {
+					sm, ok := v.(map[string]any)
+					if !ok {
+						continue
+					}
+					rewriteXGVKInbound(sm)
+					if trim {
+						schemas[name] = TrimSchema(m, sm)
+					}
+				}
+			}
+		}
+		return assignJSON(out, m)
+	}
 	return assignJSON(out, doc)
 }
 
+// rewriteXGVKInbound rewrites the group of every
+// x-kubernetes-group-version-kind entry on sm back to the caller-facing
+// group using the installed ClientMiddleware chain.
+func rewriteXGVKInbound(sm map[string]any) {
+	xgvk, _ := sm["x-kubernetes-group-version-kind"].([]any)
+	for _, e := range xgvk {
+		em, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		gStr, _ := em["group"].(string)
+		vStr, _ := em["version"].(string)
+		kStr, _ := em["kind"].(string)
+		rewritten := inboundGVK(schema.GroupVersionKind{Group: gStr, Version: vStr, Kind: kStr})
+		em["group"] = rewritten.Group
+	}
+}
+
 // List CRDs under the project control-plane
 func ListResourceDefinitions(ctx context.Context, project string, out any) error {
 	httpClient, host, err := NewProjectHTTPClient(ctx, project)
@@ -268,6 +320,7 @@ func ListResourceDefinitions(ctx context.Context, project string, out any) error
 			}
 			versions = append(versions, map[string]any{"version": v, "resources": resources})
 		}
+		g = inboundGVK(schema.GroupVersionKind{Group: g}).Group
 		groupsOut = append(groupsOut, map[string]any{"group": g, "versions": versions})
 	}
 	return assignJSON(out, map[string]any{"groups": groupsOut})
@@ -295,22 +348,64 @@ func assignJSON(out any, v any) error {
 	}
 }
 
+// TrimSchema reduces node (a schema fragment found somewhere within doc,
+// e.g. one entry of doc's components/schemas map) into a JSON-schema-lite
+// view suitable for validating MCP tool-call arguments: dramatically
+// smaller than the raw OpenAPI component, but keeping the structure and
+// validation keywords agents need to generate valid CRs. Any "$ref" is
+// resolved against doc's components/schemas map and inlined in trimmed
+// form.
+func TrimSchema(doc map[string]any, node any) any {
+	return trimToStructure(doc, node, map[string]bool{})
+}
+
+// validationKeywords are preserved verbatim on every trimmed schema node
+// alongside the structural keywords below, since agents rely on them to
+// generate valid values rather than just well-shaped ones.
+var validationKeywords = []string{
+	"enum", "format", "default", "pattern", "minimum", "maximum", "description",
+	"x-kubernetes-preserve-unknown-fields", "x-kubernetes-int-or-string", "x-kubernetes-list-type",
+}
+
 // trimToStructure returns a reduced view of an OpenAPI schema focusing on
-// structural shape: types, properties, items, required, and additionalProperties.
-func trimToStructure(v any) any {
+// structural shape (type, properties, items, required, additionalProperties)
+// plus the validationKeywords, resolving "$ref" against doc's
+// components/schemas map. visited tracks schema names on the current
+// resolution path so a cycle re-enters as {"type":"object","x-cycle":name}
+// instead of recursing forever.
+func trimToStructure(doc map[string]any, v any, visited map[string]bool) any {
 	switch t := v.(type) {
 	case map[string]any:
+		if ref, ok := t["$ref"].(string); ok {
+			name := strings.TrimPrefix(ref, "#/components/schemas/")
+			if visited[name] {
+				return map[string]any{"type": "object", "x-cycle": name}
+			}
+			target, ok := lookupComponentSchema(doc, name)
+			if !ok {
+				return map[string]any{"type": "object"}
+			}
+			visited[name] = true
+			out := trimToStructure(doc, target, visited)
+			delete(visited, name)
+			return out
+		}
+
 		out := make(map[string]any)
-		// Allowed top-level structural keys
 		if tv, ok := t["type"]; ok {
 			out["type"] = tv
 		}
 		if rp, ok := t["required"]; ok {
 			out["required"] = rp
 		}
+		for _, k := range validationKeywords {
+			if val, ok := t[k]; ok {
+				out[k] = val
+			}
+		}
 		if ap, ok := t["additionalProperties"]; ok {
 			if mp, ok := ap.(map[string]any); ok {
-				out["additionalProperties"] = trimToStructure(mp)
+				out["additionalProperties"] = trimToStructure(doc, mp, visited)
 			} else {
 				out["additionalProperties"] = ap
 			}
@@ -318,19 +413,19 @@ func trimToStructure(v any) any {
 		if props, ok := t["properties"].(map[string]any); ok {
 			trimmedProps := make(map[string]any, len(props))
 			for name, pv := range props {
-				trimmedProps[name] = trimToStructure(pv)
+				trimmedProps[name] = trimToStructure(doc, pv, visited)
 			}
 			out["properties"] = trimmedProps
 		}
 		if items, ok := t["items"]; ok {
-			out["items"] = trimToStructure(items)
+			out["items"] = trimToStructure(doc, items, visited)
 		}
 		// Keep composition keywords minimally
 		for _, k := range []string{"oneOf", "anyOf", "allOf"} {
 			if arr, ok := t[k].([]any); ok {
 				trimmed := make([]any, 0, len(arr))
 				for _, e := range arr {
-					trimmed = append(trimmed, trimToStructure(e))
+					trimmed = append(trimmed, trimToStructure(doc, e, visited))
 				}
 				out[k] = trimmed
 			}
@@ -339,7 +434,7 @@ func trimToStructure(v any) any {
 	case []any:
 		trimmed := make([]any, 0, len(t))
 		for _, e := range t {
-			trimmed = append(trimmed, trimToStructure(e))
+			trimmed = append(trimmed, trimToStructure(doc, e, visited))
 		}
 		return trimmed
 	default:
@@ -347,6 +442,20 @@ func trimToStructure(v any) any {
 	}
 }
 
+// lookupComponentSchema finds name within doc's components/schemas map.
+func lookupComponentSchema(doc map[string]any, name string) (map[string]any, bool) {
+	comps, ok := doc["components"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	schemas, ok := comps["schemas"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	sm, ok := schemas[name].(map[string]any)
+	return sm, ok
+}
+
 // httpGetJSON issues a GET and decodes JSON into an any.
 func httpGetJSON(ctx context.Context, client *http.Client, url string) (any, error) {
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -2,12 +2,14 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"golang.org/x/oauth2"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery/cached/disk"
@@ -28,32 +30,55 @@ var (
 	sharedMapperMu sync.Mutex
 )
 
-func newPrefixedClient(ctx context.Context, basePrefix string, bearer string) (ctrlclient.Client, error) {
-	apiHost, err := authutil.GetAPIHostname()
-	if err != nil {
-		return nil, err
+// newPrefixedClient builds a control-plane client for basePrefix. policy
+// configures authRoundTripper's retry behavior; pass nil to use
+// DefaultRetryPolicy. impersonate populates rest.Config.Impersonate so the
+// request acts on behalf of another subject instead of bearer's own
+// identity; pass nil to impersonate no one.
+func newPrefixedClient(ctx context.Context, basePrefix string, bearer string, apiHost string, userKey string, policy *RetryPolicy, impersonate *rest.ImpersonationConfig) (ctrlclient.Client, error) {
+	p := DefaultRetryPolicy
+	if policy != nil {
+		p = *policy
 	}
-
+	host := "https://" + strings.TrimRight(apiHost, "/")
+	hostDir := safeHostComponent(host)
+	httpCache, cacheErr := getOrCreateHTTPCache(hostDir, filepath.Join(defaultCacheBaseDir(), hostDir, "http"))
 	cfg := &rest.Config{
-		Host:        "https://" + strings.TrimRight(apiHost, "/"),
+		Host:        host,
 		BearerToken: bearer,
 		// WrapTransport to prefix base path
 		WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
 			if rt == nil {
 				rt = http.DefaultTransport
 			}
+			// Cache GETs on disk (honoring Cache-Control/ETag/Last-Modified)
+			// below the auth layer, so the cache key can fold in the
+			// Authorization header and never serves one account's response
+			// to another.
+			if cacheErr == nil {
+				rt = &cachingRoundTripper{next: rt, cache: httpCache}
+			}
 			// Inject auth so first request triggers EnsureAuth (opens browser if needed),
 			// then apply the project/org/user control-plane path prefix.
-			authed := &authRoundTripper{next: rt}
-			return &prefixRoundTripper{base: basePrefix, next: authed}
+			authed := &authRoundTripper{next: rt, userKey: userKey, policy: p}
+			prefixed := &prefixRoundTripper{base: basePrefix, next: authed}
+			return &middlewareRoundTripper{next: prefixed}
 		},
 	}
+	if impersonate != nil {
+		cfg.Impersonate = *impersonate
+	}
+	if inCluster() {
+		cfg.TLSClientConfig.CAFile = serviceAccountCAPath
+	}
 	mapper, err := getOrCreateMapper(cfg)
 	if err != nil {
 		return nil, err
 	}
 	scheme := runtime.NewScheme()
-	c, err := ctrlclient.New(cfg, ctrlclient.Options{Scheme: scheme, Mapper: mapper})
+	// NewWithWatch (rather than New) so WatchList can type-assert the
+	// returned client to ctrlclient.WithWatch without a second client build.
+	c, err := ctrlclient.NewWithWatch(cfg, ctrlclient.Options{Scheme: scheme, Mapper: mapper})
 	if err != nil {
 		return nil, err
 	}
@@ -101,77 +126,195 @@ func safeHostComponent(host string) string {
 }
 
 func bearerFromKeychain(ctx context.Context) (string, error) {
-	ts, err := authutil.GetTokenSource(ctx)
+	userKey, err := authutil.GetActiveUserKey()
 	if err != nil {
 		return "", err
 	}
-	t, err := ts.Token()
+	return bearerForUser(ctx, userKey)
+}
+
+// bearerForUser mints an access token from userKey's stored credentials,
+// auto-refreshing via the oauth2.TokenSource if it's expired. Used both by
+// the active-user path (bearerFromKeychain) and by requests pinned to a
+// specific account via SetProjectAccount.
+func bearerForUser(ctx context.Context, userKey string) (string, error) {
+	ts, err := authutil.GetTokenSourceForUser(ctx, userKey)
 	if err != nil {
 		return "", err
 	}
+	t, err := ts.Token()
+	if err != nil {
+		return "", classifyTokenError(userKey, err)
+	}
 	if t == nil || t.AccessToken == "" {
 		return "", fmt.Errorf("empty access token")
 	}
 	return t.AccessToken, nil
 }
 
-func NewUserControlPlaneClient(ctx context.Context, userID string) (ctrlclient.Client, error) {
+// classifyTokenError surfaces an OAuth2 invalid_grant (the refresh token
+// itself was revoked/expired) as a distinct, actionable error instead of a
+// generic refresh failure, so callers know to RemoveAccount(userKey) rather
+// than retry.
+func classifyTokenError(userKey string, err error) error {
+	var rErr *oauth2.RetrieveError
+	if errors.As(err, &rErr) && rErr.ErrorCode == "invalid_grant" {
+		return fmt.Errorf("account '%s' was signed out upstream (invalid_grant); remove it with the accounts tool and sign in again: %w", userKey, err)
+	}
+	return fmt.Errorf("failed to refresh token for '%s': %w", userKey, err)
+}
+
+// resolveActiveAuth resolves the bearer token and API host for the globally
+// active account. In-cluster, there's no keyring-stored active user: it
+// returns an empty bearer (authRoundTripper sources the service account
+// token per request instead) and the API host from env.
+func resolveActiveAuth(ctx context.Context) (bearer string, apiHost string, err error) {
+	if inCluster() {
+		apiHost, err = inClusterAPIHostname()
+		return "", apiHost, err
+	}
+	bearer, err = bearerFromKeychain(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	apiHost, err = authutil.GetAPIHostname()
+	if err != nil {
+		return "", "", err
+	}
+	return bearer, apiHost, nil
+}
+
+// resolveProjectAuth is resolveActiveAuth's project-scoped counterpart: it
+// resolves project's bound account (see authutil.SetProjectAccount), falling
+// back to the active user, except in-cluster where there's no keyring to
+// consult at all.
+func resolveProjectAuth(ctx context.Context, project string) (bearer string, apiHost string, userKey string, err error) {
+	if inCluster() {
+		apiHost, err = inClusterAPIHostname()
+		return "", apiHost, "", err
+	}
+	userKey, err = authutil.ResolveUserKeyForProject(project)
+	if err != nil {
+		return "", "", "", err
+	}
+	bearer, err = bearerForUser(ctx, userKey)
+	if err != nil {
+		return "", "", "", err
+	}
+	apiHost, err = authutil.GetAPIHostnameForUser(userKey)
+	if err != nil {
+		return "", "", "", err
+	}
+	return bearer, apiHost, userKey, nil
+}
+
+// CurrentAPIHostname resolves the API host for project (or the globally
+// active account if project is ""), the same way the control-plane client
+// factories do, without minting a client or token. Used to scope cache
+// invalidation (see FlushHTTPCacheForHost) to the host the caller is
+// actually talking to.
+func CurrentAPIHostname(ctx context.Context, project string) (string, error) {
+	if project != "" {
+		_, apiHost, _, err := resolveProjectAuth(ctx, project)
+		return apiHost, err
+	}
+	_, apiHost, err := resolveActiveAuth(ctx)
+	return apiHost, err
+}
+
+// NewUserControlPlaneClient builds a client for userID's control plane.
+// impersonate populates rest.Config.Impersonate so a privileged token can
+// act on behalf of another subject; pass nil for none.
+func NewUserControlPlaneClient(ctx context.Context, userID string, impersonate *rest.ImpersonationConfig) (ctrlclient.Client, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("userID is required")
 	}
-	bearer, err := bearerFromKeychain(ctx)
+	bearer, apiHost, err := resolveActiveAuth(ctx)
 	if err != nil {
 		return nil, err
 	}
 	base := "/apis/iam.miloapis.com/v1alpha1/users/" + userID + "/control-plane"
-	return newPrefixedClient(ctx, base, bearer)
+	return newPrefixedClient(ctx, base, bearer, apiHost, "", nil, impersonate)
 }
 
-func NewOrgControlPlaneClient(ctx context.Context, org string) (ctrlclient.Client, error) {
+// NewOrgControlPlaneClient builds a client for org's control plane.
+// impersonate populates rest.Config.Impersonate; pass nil for none.
+func NewOrgControlPlaneClient(ctx context.Context, org string, impersonate *rest.ImpersonationConfig) (ctrlclient.Client, error) {
 	if org == "" {
 		return nil, fmt.Errorf("organization is required")
 	}
-	bearer, err := bearerFromKeychain(ctx)
+	bearer, apiHost, err := resolveActiveAuth(ctx)
 	if err != nil {
 		return nil, err
 	}
 	base := "/apis/resourcemanager.miloapis.com/v1alpha1/organizations/" + org + "/control-plane"
-	return newPrefixedClient(ctx, base, bearer)
+	return newPrefixedClient(ctx, base, bearer, apiHost, "", nil, impersonate)
 }
 
-func NewProjectControlPlaneClient(ctx context.Context, project string) (ctrlclient.Client, error) {
+// NewProjectControlPlaneClient resolves credentials for project's bound
+// account (see authutil.SetProjectAccount), falling back to the active
+// user, so an agent managing several Datum orgs from one session can pin
+// distinct accounts per project instead of juggling env vars. impersonate
+// populates rest.Config.Impersonate so a privileged token can act on behalf
+// of another subject, e.g. delegated agent scenarios; pass nil for none.
+func NewProjectControlPlaneClient(ctx context.Context, project string, impersonate *rest.ImpersonationConfig) (ctrlclient.Client, error) {
 	if project == "" {
 		return nil, fmt.Errorf("project is required")
 	}
-	bearer, err := bearerFromKeychain(ctx)
+	bearer, apiHost, userKey, err := resolveProjectAuth(ctx, project)
 	if err != nil {
 		return nil, err
 	}
 	base := "/apis/resourcemanager.miloapis.com/v1alpha1/projects/" + project + "/control-plane"
-	return newPrefixedClient(ctx, base, bearer)
+	return newPrefixedClient(ctx, base, bearer, apiHost, userKey, nil, impersonate)
 }
 
 // NewProjectHTTPClient returns an HTTP client whose transport injects Authorization and the
 // project control-plane base path prefix. Use with absolute URLs like "https://host" + path.
+// Like NewProjectControlPlaneClient, it resolves project's bound account
+// first and falls back to the active user.
 func NewProjectHTTPClient(ctx context.Context, project string) (*http.Client, string, error) {
 	if project == "" {
 		return nil, "", fmt.Errorf("project is required")
 	}
-	apiHost, err := authutil.GetAPIHostname()
-	if err != nil {
-		return nil, "", err
+	var apiHost, userKey string
+	var err error
+	if inCluster() {
+		apiHost, err = inClusterAPIHostname()
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		userKey, err = authutil.ResolveUserKeyForProject(project)
+		if err != nil {
+			return nil, "", err
+		}
+		apiHost, err = authutil.GetAPIHostnameForUser(userKey)
+		if err != nil {
+			return nil, "", err
+		}
 	}
+	host := "https://" + strings.TrimRight(apiHost, "/")
+	hostDir := safeHostComponent(host)
+	httpCache, cacheErr := getOrCreateHTTPCache(hostDir, filepath.Join(defaultCacheBaseDir(), hostDir, "http"))
 	cfg := &rest.Config{ // host only, transport does auth+prefix
-		Host: "https://" + strings.TrimRight(apiHost, "/"),
+		Host: host,
 		WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
 			if rt == nil {
 				rt = http.DefaultTransport
 			}
-			authed := &authRoundTripper{next: rt}
+			if cacheErr == nil {
+				rt = &cachingRoundTripper{next: rt, cache: httpCache}
+			}
+			authed := &authRoundTripper{next: rt, userKey: userKey, policy: DefaultRetryPolicy}
 			base := "/apis/resourcemanager.miloapis.com/v1alpha1/projects/" + project + "/control-plane"
-			return &prefixRoundTripper{base: base, next: authed}
+			prefixed := &prefixRoundTripper{base: base, next: authed}
+			return &middlewareRoundTripper{next: prefixed}
 		},
 	}
+	if inCluster() {
+		cfg.TLSClientConfig.CAFile = serviceAccountCAPath
+	}
 	tr, err := rest.TransportFor(cfg)
 	if err != nil {
 		return nil, "", err
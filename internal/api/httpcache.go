@@ -0,0 +1,356 @@
+package api
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultHTTPCacheMaxEntries bounds the on-disk response cache per host: an
+// LLM agent iterating over resources re-reads the same handful of
+// list/schema endpoints repeatedly, so a few hundred entries comfortably
+// covers a session without the cache dir growing unbounded.
+const defaultHTTPCacheMaxEntries = 500
+
+// CacheStats is a point-in-time snapshot of a host's HTTP response cache
+// counters, returned by /datum/cache_stats.
+type CacheStats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+}
+
+// cacheEntry is the on-disk representation of one cached response, keyed by
+// cacheKey. Body is stored as the raw bytes read from the upstream response;
+// json.Marshal base64-encodes it automatically.
+type cacheEntry struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+	MaxAge     int         `json:"max_age_seconds"` // -1 if Cache-Control had no max-age
+}
+
+func (e *cacheEntry) fresh(now time.Time) bool {
+	if e.MaxAge < 0 {
+		return false
+	}
+	return now.Sub(e.StoredAt) < time.Duration(e.MaxAge)*time.Second
+}
+
+// httpResponseCache is a bounded, disk-persisted HTTP response cache for one
+// control-plane host, honoring Cache-Control max-age for freshness and
+// ETag/Last-Modified for revalidation (RFC 7234, the subset that matters for
+// a read-mostly Kubernetes API client: no shared-cache Vary/Age bookkeeping).
+// Entries are evicted least-recently-used once len(order) exceeds max.
+type httpResponseCache struct {
+	dir string
+	max int
+
+	mu    sync.Mutex
+	order *list.List               // front = most recently used
+	elems map[string]*list.Element // key -> element holding the key string
+	stats CacheStats
+}
+
+func newHTTPResponseCache(dir string, max int) (*httpResponseCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	c := &httpResponseCache{
+		dir:   dir,
+		max:   max,
+		order: list.New(),
+		elems: map[string]*list.Element{},
+	}
+	c.loadIndex()
+	return c, nil
+}
+
+// loadIndex rebuilds the LRU order from the cache dir's file mtimes (oldest
+// first) so a restart doesn't lose recency information for eviction,
+// without having to decode every entry's body up front.
+func (c *httpResponseCache) loadIndex() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	type fileInfo struct {
+		key     string
+		modTime time.Time
+	}
+	var files []fileInfo
+	for _, de := range entries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), ".json") {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, fileInfo{key: strings.TrimSuffix(de.Name(), ".json"), modTime: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		c.elems[f.key] = c.order.PushFront(f.key)
+	}
+}
+
+func (c *httpResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func (c *httpResponseCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	el, ok := c.elems[key]
+	if ok {
+		c.order.MoveToFront(el)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	b, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+func (c *httpResponseCache) put(key string, e *cacheEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(c.path(key), b, 0o600); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.elems[key]; ok {
+		c.order.MoveToFront(el)
+	} else {
+		c.elems[key] = c.order.PushFront(key)
+	}
+	for len(c.elems) > c.max {
+		back := c.order.Back()
+		if back == nil {
+			break
+		}
+		evictKey := back.Value.(string)
+		c.order.Remove(back)
+		delete(c.elems, evictKey)
+		_ = os.Remove(c.path(evictKey))
+		c.stats.Evictions++
+	}
+}
+
+func (c *httpResponseCache) recordHit()  { c.mu.Lock(); c.stats.Hits++; c.mu.Unlock() }
+func (c *httpResponseCache) recordMiss() { c.mu.Lock(); c.stats.Misses++; c.mu.Unlock() }
+
+func (c *httpResponseCache) snapshot() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// flush removes every entry from disk and zeroes the in-memory index, but
+// keeps the counters: a cache_stats caller wants to see how much churn
+// RefreshDiscovery caused, not a reset back to zero.
+func (c *httpResponseCache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		_ = os.Remove(c.path(el.Value.(string)))
+	}
+	c.order = list.New()
+	c.elems = map[string]*list.Element{}
+}
+
+// cacheKey identifies a cacheable request: method + URL, plus a hash of the
+// Authorization header so two accounts hitting the same control plane never
+// share a cached response (this cache backs a shared-per-host transport, not
+// a per-user one, so it must behave like a private cache per credential).
+func cacheKey(r *http.Request) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte("\n"))
+	h.Write([]byte(r.URL.String()))
+	h.Write([]byte("\n"))
+	h.Write([]byte(r.Header.Get("Authorization")))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// parseMaxAge reads max-age out of a Cache-Control header, returning -1 if
+// it's absent. no-store/no-cache both disable caching entirely for our
+// purposes: no-cache technically only forces revalidation, but without a
+// strong validator that's equivalent to not caching at all.
+func parseMaxAge(cacheControl string) (maxAge int, cacheable bool) {
+	if cacheControl == "" {
+		return -1, true
+	}
+	for _, part := range strings.Split(cacheControl, ",") {
+		part = strings.TrimSpace(strings.ToLower(part))
+		if part == "no-store" || part == "no-cache" {
+			return -1, false
+		}
+		if strings.HasPrefix(part, "max-age=") {
+			if n, err := strconv.Atoi(strings.TrimPrefix(part, "max-age=")); err == nil {
+				maxAge = n
+			}
+		}
+	}
+	if maxAge == 0 {
+		return -1, true
+	}
+	return maxAge, true
+}
+
+// cachingRoundTripper wraps the base transport with httpResponseCache,
+// serving fresh cached GETs directly and revalidating stale ones with
+// If-None-Match/If-Modified-Since before falling through to the network.
+// Only GET requests are considered: watches (identified by ?watch=true) and
+// mutating verbs are always forwarded untouched.
+type cachingRoundTripper struct {
+	next  http.RoundTripper
+	cache *httpResponseCache
+}
+
+func (c *cachingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method != http.MethodGet || r.URL.Query().Get("watch") == "true" {
+		return c.next.RoundTrip(r)
+	}
+
+	key := cacheKey(r)
+	entry, hit := c.cache.get(key)
+	if hit && entry.fresh(time.Now()) {
+		c.cache.recordHit()
+		return entryToResponse(entry, r), nil
+	}
+
+	req := r
+	if hit {
+		req = r.Clone(r.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lm := entry.Header.Get("Last-Modified"); lm != "" {
+			req.Header.Set("If-Modified-Since", lm)
+		}
+	}
+
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if hit && resp.StatusCode == http.StatusNotModified {
+		c.cache.recordHit()
+		drainAndClose(resp)
+		entry.StoredAt = time.Now()
+		c.cache.put(key, entry)
+		return entryToResponse(entry, r), nil
+	}
+
+	c.cache.recordMiss()
+	if resp.StatusCode == http.StatusOK {
+		maxAge, cacheable := parseMaxAge(resp.Header.Get("Cache-Control"))
+		if cacheable && (maxAge >= 0 || resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "") {
+			if body, err := readAndRestoreBody(resp); err == nil {
+				c.cache.put(key, &cacheEntry{
+					StatusCode: resp.StatusCode,
+					Header:     resp.Header.Clone(),
+					Body:       body,
+					StoredAt:   time.Now(),
+					MaxAge:     maxAge,
+				})
+			}
+		}
+	}
+	return resp, nil
+}
+
+func entryToResponse(e *cacheEntry, r *http.Request) *http.Response {
+	return &http.Response{
+		StatusCode:    e.StatusCode,
+		Status:        http.StatusText(e.StatusCode),
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       r,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+	}
+}
+
+func readAndRestoreBody(resp *http.Response) ([]byte, error) {
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+var (
+	hostCachesMu sync.Mutex
+	hostCaches   = map[string]*httpResponseCache{}
+)
+
+// getOrCreateHTTPCache returns the shared httpResponseCache for hostDir
+// (see safeHostComponent), creating it under httpCacheDir on first use.
+func getOrCreateHTTPCache(hostDir, httpCacheDir string) (*httpResponseCache, error) {
+	hostCachesMu.Lock()
+	defer hostCachesMu.Unlock()
+	if c, ok := hostCaches[hostDir]; ok {
+		return c, nil
+	}
+	c, err := newHTTPResponseCache(httpCacheDir, defaultHTTPCacheMaxEntries)
+	if err != nil {
+		return nil, err
+	}
+	hostCaches[hostDir] = c
+	return c, nil
+}
+
+// FlushHTTPCacheForHost discards every cached response for apiHost (e.g.
+// after RefreshDiscovery), so stale list/schema responses can't outlive a
+// deliberate cache-busting refresh. It's a no-op if apiHost has no cache yet.
+func FlushHTTPCacheForHost(apiHost string) {
+	hostCachesMu.Lock()
+	c, ok := hostCaches[safeHostComponent(apiHost)]
+	hostCachesMu.Unlock()
+	if ok {
+		c.flush()
+	}
+}
+
+// CacheStatsByHost returns a snapshot of every host cache's counters, keyed
+// by host, for /datum/cache_stats.
+func CacheStatsByHost() map[string]CacheStats {
+	hostCachesMu.Lock()
+	defer hostCachesMu.Unlock()
+	out := make(map[string]CacheStats, len(hostCaches))
+	for host, c := range hostCaches {
+		out[host] = c.snapshot()
+	}
+	return out
+}
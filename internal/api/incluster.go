@@ -0,0 +1,132 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/datum-cloud/datum-mcp/internal/authutil"
+)
+
+const (
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	serviceAccountCAPath    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// inCluster reports whether datum-mcp is running as an in-cluster
+// Deployment rather than a laptop CLI: KUBERNETES_SERVICE_HOST is set (the
+// usual Kubernetes-injected signal) and no DATUM_TOKEN override is present.
+// When true, the prefixed client factories and authRoundTripper source auth
+// from the projected service account instead of the keyring/interactive
+// login flow.
+func inCluster() bool {
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("DATUM_TOKEN") == ""
+}
+
+// serviceAccountTokenSource serves the projected service account token,
+// watching it via fsnotify so a kubelet-driven rotation (roughly hourly for
+// a bound token) is picked up on the next request instead of requiring a
+// restart.
+type serviceAccountTokenSource struct {
+	mu      sync.RWMutex
+	current string
+	watcher *fsnotify.Watcher
+}
+
+func newServiceAccountTokenSource() (*serviceAccountTokenSource, error) {
+	b, err := os.ReadFile(serviceAccountTokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("read service account token: %w", err)
+	}
+	ts := &serviceAccountTokenSource{current: string(b)}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		// Rotation detection is best-effort; Token() falls back to
+		// re-reading the file directly when there's no active watcher.
+		return ts, nil
+	}
+	if err := w.Add(serviceAccountTokenPath); err != nil {
+		_ = w.Close()
+		return ts, nil
+	}
+	ts.watcher = w
+	go ts.watch()
+	return ts, nil
+}
+
+func (ts *serviceAccountTokenSource) watch() {
+	for {
+		select {
+		case ev, ok := <-ts.watcher.Events:
+			if !ok {
+				return
+			}
+			// The kubelet rotates the token via an atomic symlink swap, so
+			// re-add the watch defensively alongside reloading the content.
+			_ = ts.watcher.Add(serviceAccountTokenPath)
+			_ = ev
+			ts.reload()
+		case _, ok := <-ts.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func (ts *serviceAccountTokenSource) reload() {
+	b, err := os.ReadFile(serviceAccountTokenPath)
+	if err != nil {
+		return
+	}
+	ts.mu.Lock()
+	ts.current = string(b)
+	ts.mu.Unlock()
+}
+
+// Token returns the current service account token.
+func (ts *serviceAccountTokenSource) Token() (string, error) {
+	if ts.watcher == nil {
+		ts.reload()
+	}
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	if ts.current == "" {
+		return "", fmt.Errorf("empty service account token")
+	}
+	return ts.current, nil
+}
+
+var (
+	saTokenSourceOnce sync.Once
+	saTokenSource     *serviceAccountTokenSource
+	saTokenSourceErr  error
+)
+
+// inClusterAPIHostname resolves the Datum API hostname when running
+// in-cluster, where there's no keyring-stored StoredCredentials.APIHostname
+// to fall back on: DATUM_API_HOSTNAME if set, otherwise derived from
+// DATUM_AUTH_HOSTNAME (defaulting to auth.datum.net) the same way the
+// interactive login flow does.
+func inClusterAPIHostname() (string, error) {
+	if v := os.Getenv("DATUM_API_HOSTNAME"); v != "" {
+		return v, nil
+	}
+	authHost := os.Getenv("DATUM_AUTH_HOSTNAME")
+	if authHost == "" {
+		authHost = "auth.datum.net"
+	}
+	return authutil.DeriveAPIHostname(authHost)
+}
+
+// getServiceAccountTokenSource lazily starts the single shared
+// serviceAccountTokenSource for the process, so every in-cluster
+// authRoundTripper shares one fsnotify watch instead of each opening its own.
+func getServiceAccountTokenSource() (*serviceAccountTokenSource, error) {
+	saTokenSourceOnce.Do(func() {
+		saTokenSource, saTokenSourceErr = newServiceAccountTokenSource()
+	})
+	return saTokenSource, saTokenSourceErr
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// retryLatencyBuckets are the histogram bucket upper bounds (seconds) for
+// RequestMetrics.ObserveLatency, spanning a fast in-region call (<100ms)
+// through a request that exhausted every retry (several seconds).
+var retryLatencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// RequestMetrics is a minimal Prometheus-style registry for authRoundTripper:
+// a counter of total attempts, a counter of retries broken down by reason
+// (timeout|429|5xx|401), and a request-latency histogram. It deliberately
+// avoids a full metrics client dependency; WriteProm renders the same text
+// exposition format one would, which is all an operator scraping /metrics
+// needs.
+type RequestMetrics struct {
+	attempts int64
+
+	mu              sync.Mutex
+	retriesByReason map[string]int64
+	bucketCounts    []int64 // parallel to retryLatencyBuckets; cumulative per Prometheus "le" convention
+	latencySum      float64
+	latencyCount    int64
+}
+
+func NewRequestMetrics() *RequestMetrics {
+	return &RequestMetrics{
+		retriesByReason: map[string]int64{},
+		bucketCounts:    make([]int64, len(retryLatencyBuckets)),
+	}
+}
+
+// DefaultMetrics is the registry authRoundTripper records into unless its
+// RetryPolicy supplies its own.
+var DefaultMetrics = NewRequestMetrics()
+
+func (m *RequestMetrics) RecordAttempt() {
+	atomic.AddInt64(&m.attempts, 1)
+}
+
+func (m *RequestMetrics) RecordRetry(reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retriesByReason[reason]++
+}
+
+func (m *RequestMetrics) ObserveLatency(d time.Duration) {
+	sec := d.Seconds()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latencySum += sec
+	m.latencyCount++
+	for i, upper := range retryLatencyBuckets {
+		if sec <= upper {
+			m.bucketCounts[i]++
+		}
+	}
+}
+
+// WriteProm renders m in Prometheus text exposition format.
+func (m *RequestMetrics) WriteProm() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP datum_mcp_control_plane_requests_total Total control-plane HTTP requests attempted, including retries.\n")
+	b.WriteString("# TYPE datum_mcp_control_plane_requests_total counter\n")
+	fmt.Fprintf(&b, "datum_mcp_control_plane_requests_total %d\n", atomic.LoadInt64(&m.attempts))
+
+	b.WriteString("# HELP datum_mcp_control_plane_retries_total Control-plane HTTP requests retried, by reason.\n")
+	b.WriteString("# TYPE datum_mcp_control_plane_retries_total counter\n")
+	reasons := make([]string, 0, len(m.retriesByReason))
+	for r := range m.retriesByReason {
+		reasons = append(reasons, r)
+	}
+	sort.Strings(reasons)
+	for _, r := range reasons {
+		fmt.Fprintf(&b, "datum_mcp_control_plane_retries_total{reason=%q} %d\n", r, m.retriesByReason[r])
+	}
+
+	b.WriteString("# HELP datum_mcp_control_plane_request_duration_seconds Control-plane request latency, including time spent retrying.\n")
+	b.WriteString("# TYPE datum_mcp_control_plane_request_duration_seconds histogram\n")
+	for i, upper := range retryLatencyBuckets {
+		fmt.Fprintf(&b, "datum_mcp_control_plane_request_duration_seconds_bucket{le=%q} %d\n", strconv.FormatFloat(upper, 'g', -1, 64), m.bucketCounts[i])
+	}
+	fmt.Fprintf(&b, "datum_mcp_control_plane_request_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.latencyCount)
+	fmt.Fprintf(&b, "datum_mcp_control_plane_request_duration_seconds_sum %g\n", m.latencySum)
+	fmt.Fprintf(&b, "datum_mcp_control_plane_request_duration_seconds_count %d\n", m.latencyCount)
+	return b.String()
+}
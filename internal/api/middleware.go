@@ -0,0 +1,146 @@
+package api
+
+import (
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ClientMiddleware rewrites GVKs and request paths between the caller-facing
+// API surface and whatever a given control plane actually serves on the
+// wire, modeled on Pinniped's kubeclient.WithMiddleware pattern. It lets one
+// datum-mcp binary target multiple Datum control planes (prod/staging/
+// self-hosted forks) that ship the same CRDs under different API group
+// names, without duplicating GVK tables throughout internal/api.
+type ClientMiddleware interface {
+	// RewriteOutboundGVK rewrites a caller-facing GVK into the GVK the
+	// upstream control plane actually serves.
+	RewriteOutboundGVK(gvk schema.GroupVersionKind) schema.GroupVersionKind
+	// RewriteOutboundPath rewrites an outbound request path (e.g.
+	// "/apis/<group>/<version>/...") in the same direction as
+	// RewriteOutboundGVK.
+	RewriteOutboundPath(path string) string
+	// RewriteInboundGVK rewrites a GVK surfaced in a response (e.g. an
+	// x-kubernetes-group-version-kind entry from discovery) back to the
+	// caller-facing group.
+	RewriteInboundGVK(gvk schema.GroupVersionKind) schema.GroupVersionKind
+}
+
+var (
+	sharedMiddlewareMu sync.Mutex
+	sharedMiddleware   []ClientMiddleware
+)
+
+// SetMiddleware installs the package-wide middleware chain applied by
+// resolveGVKs, NewProjectHTTPClient, and the discovery helpers. Later calls
+// replace the chain; pass no arguments to clear it.
+func SetMiddleware(mw ...ClientMiddleware) {
+	sharedMiddlewareMu.Lock()
+	defer sharedMiddlewareMu.Unlock()
+	sharedMiddleware = mw
+}
+
+func middlewareChain() []ClientMiddleware {
+	sharedMiddlewareMu.Lock()
+	defer sharedMiddlewareMu.Unlock()
+	return sharedMiddleware
+}
+
+// outboundGVK runs gvk through RewriteOutboundGVK for every installed
+// middleware, in registration order.
+func outboundGVK(gvk schema.GroupVersionKind) schema.GroupVersionKind {
+	for _, mw := range middlewareChain() {
+		gvk = mw.RewriteOutboundGVK(gvk)
+	}
+	return gvk
+}
+
+// inboundGVK runs gvk through RewriteInboundGVK in reverse registration
+// order, undoing outboundGVK.
+func inboundGVK(gvk schema.GroupVersionKind) schema.GroupVersionKind {
+	chain := middlewareChain()
+	for i := len(chain) - 1; i >= 0; i-- {
+		gvk = chain[i].RewriteInboundGVK(gvk)
+	}
+	return gvk
+}
+
+// outboundPath runs path through RewriteOutboundPath for every installed
+// middleware, in registration order.
+func outboundPath(path string) string {
+	for _, mw := range middlewareChain() {
+		path = mw.RewriteOutboundPath(path)
+	}
+	return path
+}
+
+// GroupSuffixMiddleware transparently renames a configurable API group
+// suffix (e.g. "*.datum.net" -> "*.datum-staging.net") on outbound requests
+// and undoes the rename on inbound GVKs, so CRD tables can be written once
+// against the caller-facing suffix regardless of which control plane fork
+// actually serves them.
+type GroupSuffixMiddleware struct {
+	// From is the caller-facing suffix, e.g. "datum.net".
+	From string
+	// To is the suffix the target control plane actually serves, e.g.
+	// "datum-staging.net".
+	To string
+}
+
+func (m GroupSuffixMiddleware) rewriteSuffix(group, from, to string) string {
+	// A half-configured middleware (only one of From/To set) must not
+	// rewrite at all: returning "" for a matching group here would corrupt
+	// every outbound GVK/path and every inbound discovery response instead
+	// of failing loudly, since callers have no way to tell "deliberately
+	// rewritten to the empty group" from "misconfigured". SetMiddleware
+	// callers are expected to validate From/To are set together before
+	// installing this middleware at all.
+	if from == "" || to == "" {
+		return group
+	}
+	if group == from {
+		return to
+	}
+	if strings.HasSuffix(group, "."+from) {
+		return strings.TrimSuffix(group, from) + to
+	}
+	return group
+}
+
+func (m GroupSuffixMiddleware) RewriteOutboundGVK(gvk schema.GroupVersionKind) schema.GroupVersionKind {
+	gvk.Group = m.rewriteSuffix(gvk.Group, m.From, m.To)
+	return gvk
+}
+
+func (m GroupSuffixMiddleware) RewriteInboundGVK(gvk schema.GroupVersionKind) schema.GroupVersionKind {
+	gvk.Group = m.rewriteSuffix(gvk.Group, m.To, m.From)
+	return gvk
+}
+
+// RewriteOutboundPath rewrites the "/apis/<group>/..." segment of path from
+// the caller-facing group suffix to the wire suffix. It targets the
+// rightmost "/apis/" occurrence so it rewrites the CRD group being
+// requested, not an outer control-plane routing prefix like
+// "/apis/resourcemanager.miloapis.com/.../control-plane/apis/<group>/...".
+func (m GroupSuffixMiddleware) RewriteOutboundPath(path string) string {
+	const prefix = "/apis/"
+	idx := strings.LastIndex(path, prefix)
+	if idx == -1 {
+		return path
+	}
+	rest := path[idx+len(prefix):]
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) == 0 || parts[0] == "" {
+		return path
+	}
+	newGroup := m.rewriteSuffix(parts[0], m.From, m.To)
+	if newGroup == parts[0] {
+		return path
+	}
+	tail := ""
+	if len(parts) > 1 {
+		tail = "/" + parts[1]
+	}
+	return path[:idx] + prefix + newGroup + tail
+}
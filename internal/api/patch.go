@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PatchOptions configures PatchObject. FieldManager is required for
+// types.ApplyPatchType and is otherwise optional metadata recorded in
+// managedFields.
+type PatchOptions struct {
+	FieldManager string
+	// Force is only meaningful for types.ApplyPatchType: it takes ownership
+	// of conflicting fields from other field managers.
+	Force bool
+	// DryRun requests ?dryRun=All: the patch is evaluated (and, for SSA,
+	// conflicts are still reported) but nothing is persisted.
+	DryRun bool
+}
+
+// ConflictError wraps a Server-Side Apply ownership conflict so MCP tools
+// can distinguish it from other patch failures and offer to retry with
+// Force set.
+type ConflictError struct{ Err error }
+
+func (e *ConflictError) Error() string { return e.Err.Error() }
+func (e *ConflictError) Unwrap() error { return e.Err }
+
+// PatchObject applies a partial update to an existing object using one of
+// the standard Kubernetes patch types, or Server-Side Apply
+// (types.ApplyPatchType) when a fieldManager is required. Unlike
+// UpdateObjectSpec, this never performs a read-modify-write: the patch is
+// sent to the API server as-is, so concurrent changes to fields outside the
+// patch are preserved.
+func PatchObject(ctx context.Context, cli ctrlclient.Client, group, kind, namespace, name string, patchType types.PatchType, payload []byte, opts PatchOptions) (*unstructured.Unstructured, error) {
+	objGVK, _, err := resolveGVKs(group, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj unstructured.Unstructured
+	obj.SetGroupVersionKind(objGVK)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+
+	var patch ctrlclient.Patch
+	switch patchType {
+	case types.MergePatchType, types.JSONPatchType, types.StrategicMergePatchType:
+		patch = ctrlclient.RawPatch(patchType, payload)
+	case types.ApplyPatchType:
+		if opts.FieldManager == "" {
+			return nil, fmt.Errorf("fieldManager is required for server-side apply")
+		}
+		if err := json.Unmarshal(payload, &obj.Object); err != nil {
+			return nil, fmt.Errorf("invalid apply payload: %w", err)
+		}
+		obj.SetGroupVersionKind(objGVK)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		patch = ctrlclient.Apply
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", patchType)
+	}
+
+	var patchOpts []ctrlclient.PatchOption
+	if opts.FieldManager != "" {
+		patchOpts = append(patchOpts, ctrlclient.FieldOwner(opts.FieldManager))
+	}
+	if opts.Force {
+		patchOpts = append(patchOpts, ctrlclient.ForceOwnership)
+	}
+	if opts.DryRun {
+		patchOpts = append(patchOpts, ctrlclient.DryRunAll)
+	}
+
+	if err := cli.Patch(ctx, &obj, patch, patchOpts...); err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, &ConflictError{Err: err}
+		}
+		return nil, err
+	}
+	obj.SetGroupVersionKind(inboundGVK(obj.GroupVersionKind()))
+	return &obj, nil
+}
+
+// IsConflict reports whether err is (or wraps) a Server-Side Apply
+// ownership conflict returned by PatchObject.
+func IsConflict(err error) bool {
+	var c *ConflictError
+	return errors.As(err, &c)
+}
@@ -1,10 +1,21 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
 
 	"github.com/datum-cloud/datum-mcp/internal/auth"
+	"github.com/datum-cloud/datum-mcp/internal/authutil"
 )
 
 // prefixRoundTripper injects a base path prefix into all requests.
@@ -20,31 +31,257 @@ func (p *prefixRoundTripper) RoundTrip(r *http.Request) (*http.Response, error)
 	return p.next.RoundTrip(r)
 }
 
-// authRoundTripper injects Authorization using the current token and retries once on 401/403 after EnsureAuth.
-type authRoundTripper struct{ next http.RoundTripper }
+// middlewareRoundTripper applies the installed ClientMiddleware chain's
+// RewriteOutboundPath to the request path, so raw REST calls that don't go
+// through resolveGVKs (which already translates via outboundGVK) still reach
+// the control plane's wire-facing API group.
+type middlewareRoundTripper struct{ next http.RoundTripper }
+
+func (m *middlewareRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Path = outboundPath(r.URL.Path)
+	return m.next.RoundTrip(r)
+}
+
+// RetryPolicy configures authRoundTripper's handling of transient
+// control-plane failures. The zero value is not meaningful on its own; start
+// from DefaultRetryPolicy and override individual fields.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts (the initial try plus
+	// retries) before giving up and returning the last response/error.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; each subsequent retry
+	// doubles it, capped at MaxDelay, with full jitter applied.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Metrics receives attempt/retry/latency observations; defaults to
+	// DefaultMetrics when nil.
+	Metrics *RequestMetrics
+}
+
+// DefaultRetryPolicy retries up to 5 attempts total, backing off from 200ms
+// up to a 3s cap, enough to ride out a brief API server blip without making
+// an interactive tool call hang for long.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    3 * time.Second,
+}
+
+func (p RetryPolicy) metrics() *RequestMetrics {
+	if p.Metrics != nil {
+		return p.Metrics
+	}
+	return DefaultMetrics
+}
+
+// backoff returns a full-jitter delay for the retry attempt that follows
+// attemptsSoFar: a uniform random duration in [0, BaseDelay*2^(attemptsSoFar-1)],
+// capped at MaxDelay.
+func (p RetryPolicy) backoff(attemptsSoFar int) time.Duration {
+	d := float64(p.BaseDelay) * math.Pow(2, float64(attemptsSoFar-1))
+	if max := float64(p.MaxDelay); p.MaxDelay > 0 && d > max {
+		d = max
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// authRoundTripper injects Authorization using the current token and, for
+// transient failures, retries with jittered exponential backoff: 429 honors
+// Retry-After, 502/503/504 and timing-out net.Errors back off per policy,
+// and 401 first tries a silent TokenSource refresh before falling through to
+// an interactive RunLoginFlow (only for the globally active user - a
+// project-pinned account, identified by a non-empty userKey, never triggers
+// one). 403 is an authorization failure, not an authentication one, and is
+// never retried. If userKey is set, the request authenticates as that
+// specific account (see authutil.SetProjectAccount) rather than whichever
+// account is globally active. When inCluster reports true, both the initial
+// token and any 401 retry instead come from the shared
+// serviceAccountTokenSource, and the interactive login fallback never runs.
+type authRoundTripper struct {
+	next    http.RoundTripper
+	userKey string
+	policy  RetryPolicy
+}
 
 func (a *authRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
 	if a.next == nil {
 		a.next = http.DefaultTransport
 	}
-	// initial token via EnsureAuth (may trigger login if missing)
-	if tkn, err := auth.EnsureAuth(r.Context()); err == nil && tkn != "" {
+	policy := a.policy
+	if policy.MaxAttempts <= 0 {
+		policy = DefaultRetryPolicy
+	}
+	metrics := policy.metrics()
+	start := time.Now()
+	defer func() { metrics.ObserveLatency(time.Since(start)) }()
+
+	// initial token via EnsureAuthForUser (may trigger login if missing and userKey is unset)
+	if tkn, err := a.currentToken(r.Context()); err == nil && tkn != "" {
 		r.Header.Set("Authorization", "Bearer "+tkn)
 	}
-	resp, err := a.next.RoundTrip(r)
+
+	triedInteractiveLogin := false
+	var resp *http.Response
+	var err error
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		metrics.RecordAttempt()
+		req := r
+		if attempt > 1 {
+			req, err = cloneRequest(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resp, err = a.next.RoundTrip(req)
+		last := attempt == policy.MaxAttempts
+
+		if err != nil {
+			var netErr net.Error
+			if last || !errors.As(err, &netErr) || !netErr.Timeout() {
+				return nil, err
+			}
+			metrics.RecordRetry("timeout")
+			waitForRetry(r.Context(), policy.backoff(attempt))
+			continue
+		}
+		if last {
+			return resp, nil
+		}
+
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests:
+			metrics.RecordRetry("429")
+			delay := retryAfterDelay(resp.Header.Get("Retry-After"))
+			if delay <= 0 {
+				delay = policy.backoff(attempt)
+			}
+			drainAndClose(resp)
+			waitForRetry(r.Context(), delay)
+
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			metrics.RecordRetry("5xx")
+			drainAndClose(resp)
+			waitForRetry(r.Context(), policy.backoff(attempt))
+
+		case http.StatusUnauthorized:
+			metrics.RecordRetry("401")
+			drainAndClose(resp)
+			if tkn, ok := a.silentRefresh(r.Context()); ok {
+				r.Header.Set("Authorization", "Bearer "+tkn)
+				continue
+			}
+			if !inCluster() && a.userKey == "" && !triedInteractiveLogin {
+				triedInteractiveLogin = true
+				_ = auth.RunLoginFlow(r.Context(), false)
+			}
+			if tkn, terr := a.currentToken(r.Context()); terr == nil && tkn != "" {
+				r.Header.Set("Authorization", "Bearer "+tkn)
+			}
+
+		default:
+			// Includes 403: an authorization failure, not an authentication
+			// one, so re-login wouldn't help.
+			return resp, nil
+		}
+	}
+	return resp, err
+}
+
+// currentToken returns the bearer token to use for the next request:
+// inCluster sources it from the shared serviceAccountTokenSource, otherwise
+// it's the usual keyring-backed EnsureAuthForUser (which may trigger an
+// interactive login if userKey is unset and no token is stored).
+func (a *authRoundTripper) currentToken(ctx context.Context) (string, error) {
+	if inCluster() {
+		ts, err := getServiceAccountTokenSource()
+		if err != nil {
+			return "", err
+		}
+		return ts.Token()
+	}
+	return auth.EnsureAuthForUser(ctx, a.userKey)
+}
+
+// silentRefresh asks the account's TokenSource for a token without ever
+// opening a browser or device-code prompt (oauth2.TokenSource only performs
+// a non-interactive refresh-token exchange), so a 401 caused by plain token
+// expiry can recover before resorting to an interactive login. inCluster
+// re-reads the projected service account token the same way, which is
+// likewise non-interactive.
+func (a *authRoundTripper) silentRefresh(ctx context.Context) (string, bool) {
+	if inCluster() {
+		tkn, err := a.currentToken(ctx)
+		return tkn, err == nil && tkn != ""
+	}
+	var ts oauth2.TokenSource
+	var err error
+	if a.userKey != "" {
+		ts, err = authutil.GetTokenSourceForUser(ctx, a.userKey)
+	} else {
+		ts, err = authutil.GetTokenSource(ctx)
+	}
 	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
-		// retry once with refreshed token
-		_ = resp.Body.Close()
-		r2 := r.Clone(r.Context())
-		// force a new interactive login if refresh token is invalid
-		_ = auth.RunLoginFlow(r2.Context(), false)
-		if tkn2, err2 := auth.EnsureAuth(r2.Context()); err2 == nil && tkn2 != "" {
-			r2.Header.Set("Authorization", "Bearer "+tkn2)
-			return a.next.RoundTrip(r2)
+		return "", false
+	}
+	t, err := ts.Token()
+	if err != nil || t == nil || t.AccessToken == "" {
+		return "", false
+	}
+	return t.AccessToken, true
+}
+
+// cloneRequest clones r for a retry, rewinding the body via GetBody so a
+// request with a non-empty body (create/update) can be resent; r.Clone
+// alone would reuse the already-drained Body reader.
+func cloneRequest(r *http.Request) (*http.Request, error) {
+	req := r.Clone(r.Context())
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err
 		}
+		req.Body = body
+	}
+	return req, nil
+}
+
+func drainAndClose(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+// retryAfterDelay parses a Retry-After header value, either delta-seconds or
+// an HTTP-date, returning 0 if it's absent, unparseable, or already past.
+func retryAfterDelay(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// waitForRetry blocks for d, or until ctx is canceled, whichever comes first.
+func waitForRetry(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
 	}
-	return resp, nil
 }
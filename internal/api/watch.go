@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WatchEventType mirrors the Kubernetes watch event types datum-mcp
+// surfaces to callers, after coalescing bookmarks and errors.
+type WatchEventType string
+
+const (
+	WatchEventAdded    WatchEventType = "ADDED"
+	WatchEventModified WatchEventType = "MODIFIED"
+	WatchEventDeleted  WatchEventType = "DELETED"
+)
+
+// WatchEvent is a single coalesced change to an object observed via
+// WatchList.
+type WatchEvent struct {
+	Type   WatchEventType
+	Object *unstructured.Unstructured
+}
+
+// watchBufferSize bounds the per-subscriber channel so a slow consumer
+// cannot make WatchList block the underlying watch indefinitely; once full,
+// the oldest buffered event is dropped to make room for the newest.
+const watchBufferSize = 64
+
+// WatchList is the RoutedInput-facing counterpart to FetchList: it lists
+// group/kind/namespace once to establish a starting resourceVersion, opens a
+// watch from that point, and decodes ADDED/MODIFIED/DELETED events. Unlike a
+// plain watch, it never surfaces a 410 Gone (watch history expired) as a
+// closed channel: it relists from scratch, remembers the newest
+// resourceVersion it has observed, and reopens the watch from there, so a
+// long-lived subscriber (e.g. an MCP client watching
+// for reconciliation status changes) doesn't need to notice or recover from
+// expiry itself. The returned channel is closed when ctx is canceled, or when
+// a list or the request opening a watch fails.
+func WatchList(ctx context.Context, cli ctrlclient.Client, group, kind, namespace string) (<-chan WatchEvent, error) {
+	wc, ok := cli.(ctrlclient.WithWatch)
+	if !ok {
+		return nil, fmt.Errorf("client does not support watch")
+	}
+	out := make(chan WatchEvent, watchBufferSize)
+	go runWatchList(ctx, wc, group, kind, namespace, out)
+	return out, nil
+}
+
+// runWatchList drives the relist-then-watch loop behind WatchList.
+func runWatchList(ctx context.Context, wc ctrlclient.WithWatch, group, kind, namespace string, out chan WatchEvent) {
+	defer close(out)
+	resourceVersion := ""
+	for ctx.Err() == nil {
+		if resourceVersion == "" {
+			list, err := FetchList(ctx, wc, group, kind, namespace)
+			if err != nil {
+				return
+			}
+			resourceVersion = list.GetResourceVersion()
+		}
+
+		_, listGVK, err := resolveGVKs(group, kind)
+		if err != nil {
+			return
+		}
+		var list unstructured.UnstructuredList
+		list.SetGroupVersionKind(listGVK)
+		opts := []ctrlclient.ListOption{&ctrlclient.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: resourceVersion}}}
+		if namespace != "" {
+			opts = append(opts, ctrlclient.InNamespace(namespace))
+		}
+		w, err := wc.Watch(ctx, &list, opts...)
+		if err != nil {
+			return
+		}
+		expired := drainWatchList(ctx, w, out, &resourceVersion)
+		w.Stop()
+		if expired {
+			// The watch expired (410 Gone): relist from scratch on the next
+			// iteration instead of resuming from a resourceVersion the API
+			// server has already compacted away.
+			resourceVersion = ""
+		}
+		// Otherwise the watch simply ended (e.g. a server-side timeout):
+		// reopen it from the newest resourceVersion observed so far.
+	}
+}
+
+// drainWatchList copies events from w into out, tracking the newest
+// resourceVersion seen in resourceVersion, until ctx is canceled, the watch
+// ends, or the API server reports the watch has expired. It returns true
+// only in the expired case, telling the caller to relist and resume.
+func drainWatchList(ctx context.Context, w watch.Interface, out chan WatchEvent, resourceVersion *string) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return false
+			}
+			if ev.Type == watch.Error {
+				if status, ok := ev.Object.(*metav1.Status); ok {
+					return apierrors.IsResourceExpired(&apierrors.StatusError{ErrStatus: *status})
+				}
+				continue
+			}
+			u, ok := ev.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			var wt WatchEventType
+			switch ev.Type {
+			case watch.Added:
+				wt = WatchEventAdded
+			case watch.Modified:
+				wt = WatchEventModified
+			case watch.Deleted:
+				wt = WatchEventDeleted
+			default:
+				continue
+			}
+			*resourceVersion = u.GetResourceVersion()
+			u.SetGroupVersionKind(inboundGVK(u.GroupVersionKind()))
+			sendDroppingOldest(ctx, out, WatchEvent{Type: wt, Object: u})
+		}
+	}
+}
+
+// sendDroppingOldest sends ev on out, dropping the oldest buffered event
+// first if out is full, so a slow subscriber loses history rather than
+// stalling the shared watch.
+func sendDroppingOldest(ctx context.Context, out chan WatchEvent, ev WatchEvent) {
+	select {
+	case out <- ev:
+		return
+	case <-ctx.Done():
+		return
+	default:
+	}
+	select {
+	case <-out:
+	default:
+	}
+	select {
+	case out <- ev:
+	default:
+	}
+}
@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	qrcode "github.com/skip2/go-qrcode"
+	"golang.org/x/oauth2"
+)
+
+const (
+	loginModeBrowser = "browser"
+	loginModeDevice  = "device"
+	loginModeAuto    = "auto"
+)
+
+// resolveLoginMode determines which login flow RunLoginFlow uses. It honors
+// DATUM_LOGIN_MODE (browser|device|auto), defaulting to "auto", which picks
+// the device flow when no browser can plausibly be opened (no DISPLAY on
+// Linux and not running on a desktop OS).
+func resolveLoginMode() string {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("DATUM_LOGIN_MODE")))
+	switch mode {
+	case loginModeBrowser, loginModeDevice:
+		return mode
+	}
+	if isHeadless() {
+		return loginModeDevice
+	}
+	return loginModeBrowser
+}
+
+// isHeadless is a best-effort guess at whether a browser can be opened on
+// this host: no DISPLAY/WAYLAND_DISPLAY on Linux, and not macOS/Windows
+// which always have a way to open a URL.
+func isHeadless() bool {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		return false
+	}
+	return os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == ""
+}
+
+// deviceAuthResponse is the RFC 8628 section 3.2 device authorization
+// response.
+type deviceAuthResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// providerClaims captures the subset of the OIDC discovery document needed
+// for the device flow; go-oidc does not expose device_authorization_endpoint
+// directly.
+type providerClaims struct {
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+// runDeviceLoginFlow implements the OAuth 2.0 Device Authorization Grant
+// (RFC 8628) for hosts where no local browser/loopback listener is usable
+// (containers, SSH sessions, IDE-agent subprocesses).
+func runDeviceLoginFlow(ctx context.Context, provider *oidc.Provider, clientID string, scopes []string, authHostname, apiHostname string, verbose bool) error {
+	var claims providerClaims
+	if err := provider.Claims(&claims); err != nil {
+		return fmt.Errorf("failed to read OIDC discovery document: %w", err)
+	}
+	if claims.DeviceAuthorizationEndpoint == "" {
+		return fmt.Errorf("provider at %s does not advertise a device_authorization_endpoint", authHostname)
+	}
+
+	form := url.Values{
+		"client_id": {clientID},
+		"scope":     {strings.Join(scopes, " ")},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, claims.DeviceAuthorizationEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build device authorization request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("device authorization request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	var auth deviceAuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
+		return fmt.Errorf("failed to decode device authorization response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || auth.DeviceCode == "" {
+		return fmt.Errorf("device authorization request returned status %d", resp.StatusCode)
+	}
+
+	displayURL := auth.VerificationURIComplete
+	if displayURL == "" {
+		displayURL = auth.VerificationURI
+	}
+	fmt.Fprintf(os.Stderr, "[datum-mcp] To sign in, visit %s\n", auth.VerificationURI)
+	fmt.Fprintf(os.Stderr, "[datum-mcp] and enter code: %s\n", auth.UserCode)
+	if auth.VerificationURIComplete != "" {
+		fmt.Fprintf(os.Stderr, "[datum-mcp] or open directly: %s\n", displayURL)
+	}
+	printVerificationQRCode(displayURL)
+
+	interval := auth.Interval
+	if interval <= 0 {
+		interval = 5
+	}
+	deadline := time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	if auth.ExpiresIn <= 0 {
+		deadline = time.Now().Add(10 * time.Minute)
+	}
+
+	tokenURL := provider.Endpoint().TokenURL
+	for {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("device code expired before authorization completed")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(interval) * time.Second):
+		}
+
+		token, errCode, err := pollDeviceToken(ctx, tokenURL, clientID, auth.DeviceCode)
+		switch {
+		case err == nil:
+			return persistToken(ctx, provider, clientID, scopes, authHostname, apiHostname, token, verbose)
+		case errCode == "authorization_pending":
+			continue
+		case errCode == "slow_down":
+			interval += 5
+			continue
+		case errCode == "access_denied":
+			return fmt.Errorf("authorization was denied")
+		case errCode == "expired_token":
+			return fmt.Errorf("device code expired before authorization completed")
+		default:
+			return err
+		}
+	}
+}
+
+// printVerificationQRCode prints a small ASCII QR code for verificationURL
+// to stderr, for hosts where scanning a code on a phone is faster than
+// copying the URL/user code by hand. It is purely a convenience: a failure
+// to encode never blocks the device flow, since the URL and code printed
+// above are already sufficient to complete login.
+func printVerificationQRCode(verificationURL string) {
+	if verificationURL == "" {
+		return
+	}
+	q, err := qrcode.New(verificationURL, qrcode.Medium)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, q.ToSmallString(false))
+}
+
+// pollDeviceToken issues a single RFC 8628 section 3.4 token poll. On a
+// pending/slow_down/denied/expired response it returns the RFC error code
+// (and a non-nil err) so the caller can apply the polling semantics.
+func pollDeviceToken(ctx context.Context, tokenURL, clientID, deviceCode string) (*oauth2.Token, string, error) {
+	form := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": {deviceCode},
+		"client_id":   {clientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		TokenType        string `json:"token_type"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if body.Error != "" {
+		return nil, body.Error, errors.New(strings.TrimSpace(body.Error + ": " + body.ErrorDescription))
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return nil, "", fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	token := &oauth2.Token{
+		AccessToken:  body.AccessToken,
+		TokenType:    body.TokenType,
+		RefreshToken: body.RefreshToken,
+	}
+	if body.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	if body.IDToken != "" {
+		token = token.WithExtra(map[string]any{"id_token": body.IDToken})
+	}
+	return token, "", nil
+}
+
+// loginModeFromFlag is used by cmd/datum-mcp to translate the --login-mode
+// flag into the DATUM_LOGIN_MODE env var resolveLoginMode reads, keeping
+// the flag-to-env bridge in one place.
+func loginModeFromFlag(flag string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(flag)) {
+	case "", loginModeAuto:
+		return loginModeAuto, nil
+	case loginModeBrowser:
+		return loginModeBrowser, nil
+	case loginModeDevice:
+		return loginModeDevice, nil
+	default:
+		return "", fmt.Errorf("unsupported login-mode %q (use browser|device|auto)", flag)
+	}
+}
+
+// SetLoginMode validates and applies a --login-mode flag value by exporting
+// DATUM_LOGIN_MODE for the process, mirroring how other cross-cutting
+// config (DATUM_AUTH_HOSTNAME, DATUM_CLIENT_ID, ...) flows into this
+// package via the environment.
+func SetLoginMode(flag string) error {
+	mode, err := loginModeFromFlag(flag)
+	if err != nil {
+		return err
+	}
+	return os.Setenv("DATUM_LOGIN_MODE", mode)
+}
@@ -44,6 +44,31 @@ func EnsureAuth(ctx context.Context) (string, error) {
 	return t.AccessToken, nil
 }
 
+// EnsureAuthForUser returns a valid access token for userKey's stored
+// credentials. Unlike EnsureAuth it never launches an interactive login:
+// userKey names a specific account (typically one pinned to a project via
+// authutil.SetProjectAccount), and silently authenticating as whichever
+// account the user happens to log into next would defeat the point of
+// pinning. If userKey is empty it defers to EnsureAuth's active-user
+// behavior, including the interactive login fallback.
+func EnsureAuthForUser(ctx context.Context, userKey string) (string, error) {
+	if userKey == "" {
+		return EnsureAuth(ctx)
+	}
+	ts, err := authutil.GetTokenSourceForUser(ctx, userKey)
+	if err != nil {
+		return "", err
+	}
+	t, err := ts.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh token for '%s': %w", userKey, err)
+	}
+	if t == nil || t.AccessToken == "" {
+		return "", fmt.Errorf("empty access token for '%s'", userKey)
+	}
+	return t.AccessToken, nil
+}
+
 func getenvBool(k string, def bool) bool {
 	if v := os.Getenv(k); v != "" {
 		if v == "1" || v == "true" || v == "TRUE" || v == "yes" {
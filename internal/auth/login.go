@@ -78,7 +78,9 @@ func getenvDefault(k, d string) string {
 	return d
 }
 
-// RunLoginFlow performs the PKCE OAuth2 login and stores credentials in keyring.
+// RunLoginFlow performs the OAuth2 login and stores credentials in keyring.
+// The flow used (browser redirect with PKCE, or the headless device
+// authorization grant) is selected by resolveLoginMode; see device.go.
 func RunLoginFlow(ctx context.Context, verbose bool) error {
 	authHostname, apiHostname := defaultHostnames()
 	clientID, err := resolveClientID(authHostname)
@@ -86,19 +88,15 @@ func RunLoginFlow(ctx context.Context, verbose bool) error {
 		return err
 	}
 
-	log.Printf("Starting login for %s...", authHostname)
-
 	var finalAPIHostname string
 	if apiHostname != "" {
 		finalAPIHostname = apiHostname
-		log.Printf("Using API hostname: %s", finalAPIHostname)
 	} else {
 		derivedAPI, err := authutil.DeriveAPIHostname(authHostname)
 		if err != nil {
 			return fmt.Errorf("failed to derive API hostname: %w", err)
 		}
 		finalAPIHostname = derivedAPI
-		log.Printf("Derived API hostname: %s", finalAPIHostname)
 	}
 
 	providerURL := fmt.Sprintf("https://%s", authHostname)
@@ -109,6 +107,14 @@ func RunLoginFlow(ctx context.Context, verbose bool) error {
 
 	scopes := []string{oidc.ScopeOpenID, "profile", "email", oidc.ScopeOfflineAccess}
 
+	mode := resolveLoginMode()
+	if mode == loginModeDevice {
+		return runDeviceLoginFlow(ctx, provider, clientID, scopes, authHostname, finalAPIHostname, verbose)
+	}
+
+	log.Printf("Starting login for %s...", authHostname)
+	log.Printf("Using API hostname: %s", finalAPIHostname)
+
 	listener, err := net.Listen("tcp", listenAddr)
 	if err != nil {
 		return fmt.Errorf("failed to listen on %s: %w", listenAddr, err)
@@ -201,6 +207,12 @@ func RunLoginFlow(ctx context.Context, verbose bool) error {
 	}
 	<-serverClosed
 
+	return persistToken(ctx, provider, clientID, scopes, authHostname, finalAPIHostname, token, verbose)
+}
+
+// persistToken verifies the ID token returned by either the browser or
+// device flow, then stores the resulting credentials as the active user.
+func persistToken(ctx context.Context, provider *oidc.Provider, clientID string, scopes []string, authHostname, apiHostname string, token *oauth2.Token, verbose bool) error {
 	idTokenString, ok := token.Extra("id_token").(string)
 	if !ok {
 		return fmt.Errorf("id_token not found in token response")
@@ -226,7 +238,7 @@ func RunLoginFlow(ctx context.Context, verbose bool) error {
 	userKey := claims.Email
 	creds := authutil.StoredCredentials{
 		Hostname:         authHostname,
-		APIHostname:      finalAPIHostname,
+		APIHostname:      apiHostname,
 		ClientID:         clientID,
 		EndpointAuthURL:  provider.Endpoint().AuthURL,
 		EndpointTokenURL: provider.Endpoint().TokenURL,
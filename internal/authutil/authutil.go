@@ -71,7 +71,20 @@ func GetStoredCredentials(userKey string) (*StoredCredentials, error) {
 }
 
 func GetTokenSource(ctx context.Context) (oauth2.TokenSource, error) {
-	creds, _, err := GetActiveCredentials()
+	_, userKey, err := GetActiveCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return GetTokenSourceForUser(ctx, userKey)
+}
+
+// GetTokenSourceForUser is the per-account counterpart to GetTokenSource: it
+// builds a refreshing TokenSource from userKey's stored credentials rather
+// than always using the active user, so callers that pin a project to a
+// specific account (see SetProjectAccount) can mint tokens for that account
+// without switching the global active user.
+func GetTokenSourceForUser(ctx context.Context, userKey string) (oauth2.TokenSource, error) {
+	creds, err := GetStoredCredentials(userKey)
 	if err != nil {
 		return nil, err
 	}
@@ -108,6 +121,18 @@ func GetAPIHostname() (string, error) {
 	return DeriveAPIHostname(creds.Hostname)
 }
 
+// GetAPIHostnameForUser is the per-account counterpart to GetAPIHostname.
+func GetAPIHostnameForUser(userKey string) (string, error) {
+	creds, err := GetStoredCredentials(userKey)
+	if err != nil {
+		return "", err
+	}
+	if creds.APIHostname != "" {
+		return creds.APIHostname, nil
+	}
+	return DeriveAPIHostname(creds.Hostname)
+}
+
 func DeriveAPIHostname(authHostname string) (string, error) {
 	if authHostname == "" {
 		return "", errors.New("cannot derive API hostname from empty auth hostname")
@@ -129,3 +154,162 @@ func GetSubject() (string, error) {
 	}
 	return creds.Subject, nil
 }
+
+// projectAccountKeyPrefix namespaces per-project active-account bindings
+// within ServiceName, so they don't collide with user keys (which are
+// email addresses) or ActiveUserKey/KnownUsersKey.
+const projectAccountKeyPrefix = "project_account:"
+
+func projectAccountKey(project string) string {
+	return projectAccountKeyPrefix + project
+}
+
+// SetProjectAccount pins project to the account stored under userKey (an
+// email), so NewProjectHTTPClient resolves that account's credentials for
+// this project instead of the global active user. Passing an empty userKey
+// clears the binding.
+func SetProjectAccount(project, userKey string) error {
+	if project == "" {
+		return errors.New("project is required")
+	}
+	if userKey == "" {
+		if err := keyring.Delete(ServiceName, projectAccountKey(project)); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			return fmt.Errorf("failed to clear project account for '%s': %w", project, err)
+		}
+		return nil
+	}
+	if _, err := GetStoredCredentials(userKey); err != nil {
+		return fmt.Errorf("cannot pin project '%s' to unknown account '%s': %w", project, userKey, err)
+	}
+	return keyring.Set(ServiceName, projectAccountKey(project), userKey)
+}
+
+// GetProjectAccount returns the user key bound to project via
+// SetProjectAccount, or "" if none is bound.
+func GetProjectAccount(project string) (string, error) {
+	v, err := keyring.Get(ServiceName, projectAccountKey(project))
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get project account for '%s': %w", project, err)
+	}
+	return v, nil
+}
+
+// ResolveUserKeyForProject returns the account bound to project, falling
+// back to the global active user if project is empty or has no binding.
+func ResolveUserKeyForProject(project string) (string, error) {
+	if project != "" {
+		bound, err := GetProjectAccount(project)
+		if err != nil {
+			return "", err
+		}
+		if bound != "" {
+			return bound, nil
+		}
+	}
+	return GetActiveUserKey()
+}
+
+// Account summarizes one known identity for listing/switching, without
+// exposing the underlying token.
+type Account struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Hostname string `json:"hostname"`
+	Subject  string `json:"subject"`
+	Active   bool   `json:"active"`
+}
+
+// listKnownUsers returns the raw known-users list written by addKnownUser.
+func listKnownUsers() ([]string, error) {
+	knownUsersJSON, err := keyring.Get(ServiceName, KnownUsersKey)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get known users list: %w", err)
+	}
+	if knownUsersJSON == "" {
+		return nil, nil
+	}
+	var knownUsers []string
+	if err := json.Unmarshal([]byte(knownUsersJSON), &knownUsers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal known users list: %w", err)
+	}
+	return knownUsers, nil
+}
+
+// ListAccounts returns every known account, flagging which one is the
+// current active user. Accounts whose stored credentials can no longer be
+// read (e.g. removed out of band) are skipped rather than failing the list.
+func ListAccounts() ([]Account, error) {
+	knownUsers, err := listKnownUsers()
+	if err != nil {
+		return nil, err
+	}
+	activeKey, _ := GetActiveUserKey()
+	accounts := make([]Account, 0, len(knownUsers))
+	for _, k := range knownUsers {
+		creds, err := GetStoredCredentials(k)
+		if err != nil {
+			continue
+		}
+		accounts = append(accounts, Account{Email: creds.UserEmail, Name: creds.UserName, Hostname: creds.Hostname, Subject: creds.Subject, Active: k == activeKey})
+	}
+	return accounts, nil
+}
+
+// GetActiveAccount returns the summary for the current active account, or
+// ErrNoActiveUser if none is set.
+func GetActiveAccount() (*Account, error) {
+	creds, _, err := GetActiveCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return &Account{Email: creds.UserEmail, Name: creds.UserName, Hostname: creds.Hostname, Subject: creds.Subject, Active: true}, nil
+}
+
+// SwitchAccount makes userKey the active user for subsequent requests that
+// aren't pinned to a project via SetProjectAccount.
+func SwitchAccount(userKey string) error {
+	if _, err := GetStoredCredentials(userKey); err != nil {
+		return fmt.Errorf("cannot switch to unknown account '%s': %w", userKey, err)
+	}
+	return keyring.Set(ServiceName, ActiveUserKey, userKey)
+}
+
+// RemoveAccount deletes userKey's stored credentials and known-users entry.
+// If userKey is the active user, the active-user binding is cleared too
+// (the caller must SwitchAccount to a remaining one before further use).
+// This is how stale accounts (e.g. ones whose refresh token was revoked,
+// surfaced as invalid_grant on the next token refresh) get pruned.
+func RemoveAccount(userKey string) error {
+	knownUsers, err := listKnownUsers()
+	if err != nil {
+		return err
+	}
+	filtered := make([]string, 0, len(knownUsers))
+	for _, k := range knownUsers {
+		if k != userKey {
+			filtered = append(filtered, k)
+		}
+	}
+	updatedJSON, err := json.Marshal(filtered)
+	if err != nil {
+		return fmt.Errorf("failed to marshal known users: %w", err)
+	}
+	if err := keyring.Set(ServiceName, KnownUsersKey, string(updatedJSON)); err != nil {
+		return fmt.Errorf("failed to store known users: %w", err)
+	}
+	if err := keyring.Delete(ServiceName, userKey); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("failed to remove credentials for '%s': %w", userKey, err)
+	}
+	if active, err := GetActiveUserKey(); err == nil && active == userKey {
+		if err := keyring.Delete(ServiceName, ActiveUserKey); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			return fmt.Errorf("failed to clear active user: %w", err)
+		}
+	}
+	return nil
+}
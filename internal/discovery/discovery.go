@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -22,6 +24,67 @@ const (
 	defaultDir  = "config/crd/bases"
 )
 
+// SchemaSource is one upstream CRD source Refresh ingests, in Sources
+// order. Repo is either a GitHub "owner/repo" slug (Ref/Dir select the
+// branch/tag and directory within it), or a "file://" URL or bare local
+// path to read straight off disk instead — for developing against a
+// checked-out fork without hitting GitHub at all.
+type SchemaSource struct {
+	Repo string
+	Ref  string
+	Dir  string
+	// Priority breaks ties when two sources register the same (api, kind):
+	// the higher Priority wins regardless of Sources order. Sources of
+	// equal priority fall back to Sources order (later overrides earlier),
+	// which is what a "Datum's CRDs + a cluster-specific fork overlay"
+	// setup expects without having to juggle priorities at all.
+	Priority int
+}
+
+func (s SchemaSource) effectiveRef() string {
+	if s.Ref != "" {
+		return s.Ref
+	}
+	return defaultRef
+}
+
+func (s SchemaSource) effectiveDir() string {
+	if s.Dir != "" {
+		return s.Dir
+	}
+	return defaultDir
+}
+
+// label identifies s for provenance (CRDInfo.Source) and as its on-disk
+// cache key, distinct from every other configured source.
+func (s SchemaSource) label() string {
+	if dir, ok := localSourceDir(s.Repo); ok {
+		return "file://" + filepath.Join(dir, s.effectiveDir())
+	}
+	return fmt.Sprintf("%s@%s/%s", s.Repo, s.effectiveRef(), s.effectiveDir())
+}
+
+// localSourceDir reports whether repo names a local directory rather than a
+// GitHub "owner/repo" slug: a "file://" URL, or a path that exists on disk
+// as a directory.
+func localSourceDir(repo string) (string, bool) {
+	if after, ok := strings.CutPrefix(repo, "file://"); ok {
+		return after, true
+	}
+	if info, err := os.Stat(repo); err == nil && info.IsDir() {
+		return repo, true
+	}
+	return "", false
+}
+
+// schemaProvenance records which SchemaSource last won ownership of a
+// registered (api, kind), so ListCRDs can report it and registerSchema can
+// enforce Priority ordering on the next collision.
+type schemaProvenance struct {
+	source   string
+	priority int
+}
+
 // Cache loads CRD/OpenAPI schemas and builds lookup tables used by service tools:
 //   - fullSchema[(api,kind)]
 //   - kind2api[kind] -> []apiVersions
@@ -31,33 +94,64 @@ type Cache struct {
 	mu   sync.RWMutex
 	http *http.Client
 
-	// Config
-	GitHubRepo string
-	GitHubRef  string
-	GitHubDir  string
+	// Sources are the upstream CRD repositories/directories Refresh ingests,
+	// in order; later sources override earlier ones for the same (api,
+	// kind) subject to Priority (see SchemaSource). Defaults to Datum's own
+	// network-services-operator CRDs.
+	Sources []SchemaSource
 
 	// Optional control-plane OpenAPI base; if set, we try it first.
 	OpenAPIBase string
 	BearerToken string
 
+	// KubeAPIBase, when set, is the base URL of a live Kubernetes/control-plane
+	// API server (e.g. "https://host:6443") Refresh walks directly: it lists
+	// every served group-version from /api and /apis, then ingests each
+	// one's /openapi/v3 schema via fetchAndIngestOpenAPI, the same way
+	// OpenAPIBase ingests a single pre-merged document. This is how the
+	// cache picks up CRDs actually installed on the target cluster instead
+	// of only what's committed to Sources. Auth reuses BearerToken.
+	KubeAPIBase string
+
+	// CacheDir persists fetched GitHub bodies plus their ETag/Last-Modified
+	// headers across process restarts, and the commit SHA behind them, so
+	// Refresh can conditionally-request (or skip entirely) instead of
+	// re-downloading the whole CRD directory every call. Defaults to
+	// "$XDG_CACHE_HOME/datum-mcp/discovery" (via os.UserCacheDir) when empty.
+	CacheDir string
+
+	// LastRefresh and SourceRevision are set at the end of every Refresh,
+	// including one that returns errors for some sources, for
+	// observability: LastRefresh is when it ran, SourceRevision
+	// is the commit SHA Sources[0] (the primary source) resolved to, empty
+	// if it's a local source or the revision lookup failed. Per-source
+	// revisions beyond Sources[0] aren't tracked here; see CRDInfo.Source
+	// via ListCRDs for which source a given GVK actually came from.
+	LastRefresh    time.Time
+	SourceRevision string
+
 	// Data
-	allowed    map[string]map[string]struct{} // key(api|kind) -> set(spec.*)
-	topAllowed map[string]map[string]struct{} // key(api|kind) -> set(top-level props)
-	kind2api   map[string][]string            // kind -> [apiVersion]
-	fullSchema map[string]map[string]any      // key(api|kind) -> OpenAPI fragment
+	allowed        map[string]map[string]struct{} // key(api|kind) -> set(spec.*)
+	topAllowed     map[string]map[string]struct{} // key(api|kind) -> set(top-level props)
+	kind2api       map[string][]string            // kind -> [apiVersion]
+	fullSchema     map[string]map[string]any      // key(api|kind) -> OpenAPI fragment
+	components     map[string]map[string]any      // schema name -> OpenAPI component, for Validate's $ref resolution
+	provenance     map[string]schemaProvenance    // key(api|kind) -> owning SchemaSource
+	compProvenance map[string]schemaProvenance    // component name -> owning SchemaSource
 }
 
 func New() *Cache {
 	return &Cache{
-		http:       &http.Client{Timeout: 30 * time.Second},
-		GitHubRepo: defaultRepo,
-		GitHubRef:  defaultRef,
-		GitHubDir:  defaultDir,
+		http:    &http.Client{Timeout: 30 * time.Second},
+		Sources: []SchemaSource{{Repo: defaultRepo, Ref: defaultRef, Dir: defaultDir}},
 
-		allowed:    make(map[string]map[string]struct{}),
-		topAllowed: make(map[string]map[string]struct{}),
-		kind2api:   make(map[string][]string),
-		fullSchema: make(map[string]map[string]any),
+		allowed:        make(map[string]map[string]struct{}),
+		topAllowed:     make(map[string]map[string]struct{}),
+		kind2api:       make(map[string][]string),
+		fullSchema:     make(map[string]map[string]any),
+		components:     make(map[string]map[string]any),
+		provenance:     make(map[string]schemaProvenance),
+		compProvenance: make(map[string]schemaProvenance),
 	}
 }
 
@@ -70,6 +164,9 @@ func (c *Cache) Reset() {
 	c.topAllowed = make(map[string]map[string]struct{})
 	c.kind2api = make(map[string][]string)
 	c.fullSchema = make(map[string]map[string]any)
+	c.components = make(map[string]map[string]any)
+	c.provenance = make(map[string]schemaProvenance)
+	c.compProvenance = make(map[string]schemaProvenance)
 }
 
 func (c *Cache) Refresh(ctx context.Context) error {
@@ -80,15 +177,71 @@ func (c *Cache) Refresh(ctx context.Context) error {
 
 	// Optional control-plane OpenAPI first (non-fatal if it fails).
 	if c.OpenAPIBase != "" {
-		_ = c.fetchAndIngestOpenAPI(ctx, strings.TrimRight(c.OpenAPIBase, "/")+"/openapi/v3")
+		_ = c.fetchAndIngestOpenAPI(ctx, strings.TrimRight(c.OpenAPIBase, "/")+"/openapi/v3", "openapi-base", 0)
 	}
 
-	// GitHub CRD directory listing
-	listURL := fmt.Sprintf("https://api.github.com/repos/%s/contents/%s?ref=%s", c.GitHubRepo, c.GitHubDir, c.GitHubRef)
+	// Optional live-cluster discovery (non-fatal if it fails; a cluster
+	// that's briefly unreachable shouldn't blank out whatever Sources still
+	// has to offer).
+	if c.KubeAPIBase != "" {
+		_ = c.fetchAndIngestKubeAPI(ctx)
+	}
+
+	// Sources ingest in order, each overriding earlier ones for any (api,
+	// kind) it also registers (see registerSchema/SchemaSource.Priority). A
+	// source's own failure doesn't abort the rest: one broken fork shouldn't
+	// hide schemas every other configured source is happy to serve.
+	var errs []error
+	c.SourceRevision = ""
+	for i, src := range c.Sources {
+		sha, err := c.refreshSource(ctx, src)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("source %s: %w", src.label(), err))
+			continue
+		}
+		if i == 0 && sha != "" {
+			c.SourceRevision = sha
+		}
+	}
+	c.LastRefresh = time.Now()
+	return errors.Join(errs...)
+}
+
+// refreshSource ingests one configured SchemaSource, dispatching to a local
+// directory read or a GitHub walk depending on src.Repo. It returns the
+// commit SHA the source resolved to, or "" for a local source (there's
+// nothing to pin a revision to) or if revision resolution failed.
+func (c *Cache) refreshSource(ctx context.Context, src SchemaSource) (string, error) {
+	if dir, ok := localSourceDir(src.Repo); ok {
+		return "", c.ingestLocalDir(filepath.Join(dir, src.effectiveDir()), src.label(), src.Priority)
+	}
+	return c.refreshGitHubSource(ctx, src)
+}
+
+// refreshGitHubSource is the GitHub-backed half of refreshSource: it asks
+// for src.Repo@ref's current commit SHA first, a single small request that
+// lets it skip the whole directory walk below when the SHA matches what's
+// already cached on disk from a prior Refresh.
+func (c *Cache) refreshGitHubSource(ctx context.Context, src SchemaSource) (string, error) {
+	ref := src.effectiveRef()
+	dir := src.effectiveDir()
+	label := src.label()
+
+	sha := c.resolveSourceRevision(ctx, src.Repo, ref)
+	meta := c.loadDiskMetaFor(label)
+	if sha != "" && sha == meta.SourceRevision && len(meta.Files) > 0 {
+		if err := c.ingestCachedFiles(meta.Files, label, src.Priority); err == nil {
+			return sha, nil
+		}
+		// Disk cache is incomplete or corrupt; fall through to a real walk.
+	}
+
+	listURL := fmt.Sprintf("https://api.github.com/repos/%s/contents/%s?ref=%s", src.Repo, dir, ref)
 	var entries []map[string]any
 	if err := c.getJSON(ctx, listURL, &entries); err != nil {
-		return fmt.Errorf("fetch GitHub dir: %w", err)
+		return sha, fmt.Errorf("fetch GitHub dir: %w", err)
 	}
+	var files []string
 	for _, it := range entries {
 		if it["type"] != "file" {
 			continue
@@ -100,20 +253,84 @@ func (c *Cache) Refresh(ctx context.Context) error {
 		downloadURL, _ := it["download_url"].(string)
 		if downloadURL == "" {
 			path, _ := it["path"].(string)
-			downloadURL = fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", c.GitHubRepo, c.GitHubRef, strings.TrimLeft(path, "/"))
+			downloadURL = fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", src.Repo, ref, strings.TrimLeft(path, "/"))
 		}
 		raw, err := c.getBytes(ctx, downloadURL)
 		if err != nil {
-			return fmt.Errorf("fetch %s: %w", downloadURL, err)
+			return sha, fmt.Errorf("fetch %s: %w", downloadURL, err)
 		}
-		if err := c.ingestDocs(raw); err != nil {
-			return fmt.Errorf("ingest %s: %w", downloadURL, err)
+		if err := c.ingestDocs(raw, label, src.Priority); err != nil {
+			return sha, fmt.Errorf("ingest %s: %w", downloadURL, err)
+		}
+		files = append(files, downloadURL)
+	}
+
+	if sha != "" {
+		c.storeDiskMetaFor(label, diskMeta{SourceRevision: sha, Files: files})
+	}
+	return sha, nil
+}
+
+// ingestLocalDir reads every *.yaml/*.yml file directly out of dir for a
+// file:// or bare-local-path SchemaSource: no network involved, so there's
+// no disk-cache bookkeeping to do either.
+func (c *Cache) ingestLocalDir(dir, source string, priority int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read dir %s: %w", dir, err)
+	}
+	for _, de := range entries {
+		if de.IsDir() {
+			continue
+		}
+		name := de.Name()
+		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		if err := c.ingestDocs(raw, source, priority); err != nil {
+			return fmt.Errorf("ingest %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// resolveSourceRevision fetches repo@ref's current commit SHA via GitHub's
+// commits API. Failure (offline, rate-limited, private repo without
+// GITHUB_TOKEN) is non-fatal: refreshGitHubSource just falls back to an
+// unconditional directory walk.
+func (c *Cache) resolveSourceRevision(ctx context.Context, repo, ref string) string {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/commits/%s", repo, ref)
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	if err := c.getJSON(ctx, url, &commit); err != nil {
+		return ""
+	}
+	return commit.SHA
+}
+
+// ingestCachedFiles re-parses previously downloaded CRD files straight from
+// the on-disk response cache, with no network access at all. It's only
+// called once refreshGitHubSource has confirmed the source's commit SHA
+// hasn't moved since these files were fetched.
+func (c *Cache) ingestCachedFiles(files []string, source string, priority int) error {
+	for _, url := range files {
+		entry, ok := c.loadDiskEntry(url)
+		if !ok {
+			return fmt.Errorf("no disk cache entry for %s", url)
+		}
+		if err := c.ingestDocs(entry.Body, source, priority); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (c *Cache) fetchAndIngestOpenAPI(ctx context.Context, url string) error {
+func (c *Cache) fetchAndIngestOpenAPI(ctx context.Context, url, source string, priority int) error {
 	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
 	req.Header.Set("User-Agent", "datum-mcp/2.2 (+go)")
 	req.Header.Set("Accept", "application/json, */*")
@@ -136,13 +353,120 @@ func (c *Cache) fetchAndIngestOpenAPI(ctx context.Context, url string) error {
 			return err
 		}
 	}
-	return c.ingestOpenAPI(spec)
+	return c.ingestOpenAPI(spec, source, priority)
 }
 
-func (c *Cache) getJSON(ctx context.Context, url string, into any) error {
+// aggregatedDiscoveryAccept asks /apis for the aggregated discovery document
+// (one request enumerates every group/version/resource) while still
+// accepting the plain APIGroupList a cluster without aggregated discovery
+// enabled (pre-1.30, or disabled via feature gate) falls back to serving.
+const aggregatedDiscoveryAccept = "application/json;g=apidiscovery.k8s.io;v=v2;as=APIGroupDiscoveryList,application/json"
+
+// apiVersions mirrors the /api response: the core ("") group's served versions.
+type apiVersions struct {
+	Versions []string `json:"versions"`
+}
+
+// apiGroupList mirrors the plain (non-aggregated) /apis response.
+type apiGroupList struct {
+	Groups []struct {
+		Name     string `json:"name"`
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	} `json:"groups"`
+}
+
+// apiGroupDiscoveryList is the minimal shape of the aggregated discovery
+// document served for /apis under aggregatedDiscoveryAccept. It carries a
+// lot more per-resource detail than this; we only need group+version pairs
+// to build /openapi/v3 URLs from.
+type apiGroupDiscoveryList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	} `json:"items"`
+}
+
+// fetchAndIngestKubeAPI walks a live Kubernetes/control-plane API server's
+// discovery endpoints and ingests the OpenAPI v3 schema for every served
+// group-version, so the cache picks up whatever CRDs are actually installed
+// on the target cluster instead of only what Sources has committed.
+// Failures for individual groups are collected and returned together rather
+// than aborting the walk, since one broken group shouldn't hide schemas the
+// rest of the cluster is happy to serve.
+func (c *Cache) fetchAndIngestKubeAPI(ctx context.Context) error {
+	base := strings.TrimRight(c.KubeAPIBase, "/")
+	var errs []error
+
+	// Core group ("", e.g. Pod/ConfigMap) via /api; it has no aggregated
+	// discovery document, just the version list.
+	var core apiVersions
+	if err := c.getJSONAuthed(ctx, base+"/api", "application/json", &core); err != nil {
+		errs = append(errs, fmt.Errorf("list /api: %w", err))
+	}
+	for _, v := range core.Versions {
+		if err := c.fetchAndIngestOpenAPI(ctx, base+"/openapi/v3/api/"+v, "kube-api:"+base, 0); err != nil {
+			errs = append(errs, fmt.Errorf("ingest core/%s: %w", v, err))
+		}
+	}
+
+	// Named groups (CRDs among them) via /apis.
+	gvs, err := c.listGroupVersions(ctx, base)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("list /apis: %w", err))
+	}
+	for _, gv := range gvs {
+		url := fmt.Sprintf("%s/openapi/v3/apis/%s/%s", base, gv[0], gv[1])
+		if err := c.fetchAndIngestOpenAPI(ctx, url, "kube-api:"+base, 0); err != nil {
+			errs = append(errs, fmt.Errorf("ingest %s/%s: %w", gv[0], gv[1], err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// listGroupVersions returns every served (group, version) pair from /apis,
+// preferring the aggregated discovery document (one round trip) and falling
+// back to the plain APIGroupList a cluster without it enabled still serves.
+func (c *Cache) listGroupVersions(ctx context.Context, base string) ([][2]string, error) {
+	var agg apiGroupDiscoveryList
+	if err := c.getJSONAuthed(ctx, base+"/apis", aggregatedDiscoveryAccept, &agg); err == nil && len(agg.Items) > 0 {
+		var out [][2]string
+		for _, g := range agg.Items {
+			for _, v := range g.Versions {
+				out = append(out, [2]string{g.Metadata.Name, v.Version})
+			}
+		}
+		return out, nil
+	}
+
+	var groups apiGroupList
+	if err := c.getJSONAuthed(ctx, base+"/apis", "application/json", &groups); err != nil {
+		return nil, err
+	}
+	var out [][2]string
+	for _, g := range groups.Groups {
+		for _, v := range g.Versions {
+			out = append(out, [2]string{g.Name, v.Version})
+		}
+	}
+	return out, nil
+}
+
+// getJSONAuthed is getJSON plus a Bearer token, for talking to KubeAPIBase
+// (a live cluster) rather than the public GitHub API.
+func (c *Cache) getJSONAuthed(ctx context.Context, url, accept string, into any) error {
 	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
 	req.Header.Set("User-Agent", "datum-mcp/2.2 (+go)")
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept", accept)
+	if c.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.BearerToken)
+	}
 	res, err := c.http.Do(req)
 	if err != nil {
 		return err
@@ -154,22 +478,68 @@ func (c *Cache) getJSON(ctx context.Context, url string, into any) error {
 	return json.NewDecoder(res.Body).Decode(into)
 }
 
+func (c *Cache) getJSON(ctx context.Context, url string, into any) error {
+	body, err := c.getCachedBytes(ctx, url, "application/json")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, into)
+}
+
 func (c *Cache) getBytes(ctx context.Context, url string) ([]byte, error) {
+	return c.getCachedBytes(ctx, url, "*/*")
+}
+
+// getCachedBytes is getJSON/getBytes' shared implementation: it consults the
+// on-disk response cache for url, sends a conditional request carrying
+// whatever ETag/Last-Modified it has on hand, and on HTTP 304 returns the
+// cached body instead of re-downloading it. This (plus resolveSourceRevision
+// short-circuiting Refresh entirely) is what keeps repeated Refresh calls
+// from burning through GitHub's unauthenticated 60/hour rate limit.
+func (c *Cache) getCachedBytes(ctx context.Context, url, accept string) ([]byte, error) {
+	cached, haveCached := c.loadDiskEntry(url)
+
 	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
 	req.Header.Set("User-Agent", "datum-mcp/2.2 (+go)")
-	req.Header.Set("Accept", "*/*")
+	req.Header.Set("Accept", accept)
+	if tok := os.Getenv("GITHUB_TOKEN"); tok != "" && strings.Contains(url, "github") {
+		req.Header.Set("Authorization", "token "+tok)
+	}
+	if haveCached {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
 	res, err := c.http.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified && haveCached {
+		return cached.Body, nil
+	}
 	if res.StatusCode/100 != 2 {
 		return nil, fmt.Errorf("HTTP %d", res.StatusCode)
 	}
-	return io.ReadAll(res.Body)
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.storeDiskEntry(url, &diskEntry{
+		Body:         body,
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+		StoredAt:     time.Now(),
+	})
+	return body, nil
 }
 
-func (c *Cache) ingestDocs(raw []byte) error {
+func (c *Cache) ingestDocs(raw []byte, source string, priority int) error {
 	// Detect JSON vs YAML (possibly multi-doc)
 	var first byte
 	for _, b := range raw {
@@ -182,7 +552,7 @@ func (c *Cache) ingestDocs(raw []byte) error {
 	if first == '{' || first == '[' {
 		var m map[string]any
 		if err := json.Unmarshal(raw, &m); err == nil {
-			return c.ingestOpenAPI(m)
+			return c.ingestOpenAPI(m, source, priority)
 		}
 	}
 	dec := yaml.NewDecoder(strings.NewReader(string(raw)))
@@ -199,7 +569,7 @@ func (c *Cache) ingestDocs(raw []byte) error {
 		}
 		if k, _ := doc["kind"].(string); k == "CustomResourceDefinition" {
 			if apiv, _ := doc["apiVersion"].(string); strings.HasPrefix(apiv, "apiextensions.k8s.io/") {
-				if err := c.ingestCRD(doc); err != nil {
+				if err := c.ingestCRD(doc, source, priority); err != nil {
 					return err
 				}
 				continue
@@ -207,7 +577,7 @@ func (c *Cache) ingestDocs(raw []byte) error {
 		}
 		if comp, ok := doc["components"].(map[string]any); ok {
 			if _, ok := comp["schemas"].(map[string]any); ok {
-				if err := c.ingestOpenAPI(doc); err != nil {
+				if err := c.ingestOpenAPI(doc, source, priority); err != nil {
 					return err
 				}
 			}
@@ -216,9 +586,32 @@ func (c *Cache) ingestDocs(raw []byte) error {
 	return nil
 }
 
-func (c *Cache) ingestOpenAPI(spec map[string]any) error {
+func (c *Cache) ingestOpenAPI(spec map[string]any, source string, priority int) error {
 	comps, _ := spec["components"].(map[string]any)
 	schemas, _ := comps["schemas"].(map[string]any)
+
+	// Record every named component, not just the ones with a GVK, so
+	// Validate can resolve a "$ref" into a shared definition (e.g.
+	// io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta) even though that
+	// definition is never itself registered under an api/kind. Guarded by
+	// compProvenance the same way registerSchema guards fullSchema: common
+	// component names are shared across Sources, so without this a later,
+	// lower-priority source would silently clobber a higher-priority one's
+	// copy purely by Sources iteration order.
+	c.mu.Lock()
+	for name, v := range schemas {
+		s, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		if prev, ok := c.compProvenance[name]; ok && prev.priority > priority {
+			continue
+		}
+		c.compProvenance[name] = schemaProvenance{source: source, priority: priority}
+		c.components[name] = s
+	}
+	c.mu.Unlock()
+
 	for _, v := range schemas {
 		s, _ := v.(map[string]any)
 		if s == nil {
@@ -240,13 +633,13 @@ func (c *Cache) ingestOpenAPI(spec map[string]any) error {
 			if group != "" {
 				api = group + "/" + version
 			}
-			c.registerSchema(api, kind, s)
+			c.registerSchema(source, priority, api, kind, s)
 		}
 	}
 	return nil
 }
 
-func (c *Cache) ingestCRD(crd map[string]any) error {
+func (c *Cache) ingestCRD(crd map[string]any, source string, priority int) error {
 	spec, _ := crd["spec"].(map[string]any)
 	group, _ := spec["group"].(string)
 	names, _ := spec["names"].(map[string]any)
@@ -280,16 +673,25 @@ func (c *Cache) ingestCRD(crd map[string]any) error {
 			schema["properties"] = map[string]any{}
 		}
 		api := group + "/" + ver
-		c.registerSchema(api, kind, schema)
+		c.registerSchema(source, priority, api, kind, schema)
 	}
 	return nil
 }
 
-func (c *Cache) registerSchema(api, kind string, schema map[string]any) {
+// registerSchema records schema as the definition for (api, kind), unless a
+// higher-priority source already claims it: source/priority identify the
+// SchemaSource calling in (see SchemaSource.Priority) so a later, lower-
+// priority source can't clobber an override a higher-priority one set up.
+func (c *Cache) registerSchema(source string, priority int, api, kind string, schema map[string]any) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	keyAK := joinKey(api, kind)
+	if prev, ok := c.provenance[keyAK]; ok && prev.priority > priority {
+		return
+	}
+	c.provenance[keyAK] = schemaProvenance{source: source, priority: priority}
+
 	c.fullSchema[keyAK] = schema
 
 	// kind -> apiVersions (unique append)
@@ -316,12 +718,42 @@ func (c *Cache) registerSchema(api, kind string, schema map[string]any) {
 	// spec.* collection if present
 	if sp, ok := props["spec"].(map[string]any); ok {
 		aset := make(map[string]struct{})
-		c.collectPaths(sp, "spec", aset)
+		c.collectPaths(sp, "spec", aset, map[string]bool{})
 		c.allowed[keyAK] = aset
 	}
 }
 
-func (c *Cache) collectPaths(node map[string]any, base string, out map[string]struct{}) {
+// resolveSchema follows schema's "$ref" into the components captured at
+// ingest time, returning schema unchanged if it isn't a $ref node. The
+// returned done must be deferred by the caller around its use of the
+// resolved schema, including any recursive descent into its fields: that
+// keeps the $ref's component name on the "recursion stack" tracked by
+// visited for exactly as long as the caller is inside it, so a schema that
+// eventually refs back to itself (not uncommon for recursive
+// JSONSchemaProps-style CRD validation schemas) stops instead of recursing
+// forever.
+func (c *Cache) resolveSchema(schema map[string]any, visited map[string]bool) (resolved map[string]any, done func()) {
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema, func() {}
+	}
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	if visited[name] {
+		return map[string]any{}, func() {}
+	}
+	c.mu.RLock()
+	target, ok := c.components[name]
+	c.mu.RUnlock()
+	if !ok {
+		return map[string]any{}, func() {}
+	}
+	visited[name] = true
+	return target, func() { delete(visited, name) }
+}
+
+func (c *Cache) collectPaths(node map[string]any, base string, out map[string]struct{}, visited map[string]bool) {
+	node, done := c.resolveSchema(node, visited)
+	defer done()
 	if node == nil {
 		return
 	}
@@ -333,7 +765,7 @@ func (c *Cache) collectPaths(node map[string]any, base string, out map[string]st
 		if arr, ok := node[key].([]any); ok {
 			for _, it := range arr {
 				if m, ok := it.(map[string]any); ok {
-					c.collectPaths(m, base, out)
+					c.collectPaths(m, base, out, visited)
 				}
 			}
 		}
@@ -350,13 +782,13 @@ func (c *Cache) collectPaths(node map[string]any, base string, out map[string]st
 			here += k
 			out[here] = struct{}{}
 			if sm, ok := sub.(map[string]any); ok {
-				c.collectPaths(sm, here, out)
+				c.collectPaths(sm, here, out, visited)
 			}
 		}
 		switch ap := node["additionalProperties"].(type) {
 		case map[string]any:
 			out[base+".*"] = struct{}{}
-			c.collectPaths(ap, base, out)
+			c.collectPaths(ap, base, out, visited)
 		case bool:
 			if ap {
 				out[base+".*"] = struct{}{}
@@ -364,25 +796,34 @@ func (c *Cache) collectPaths(node map[string]any, base string, out map[string]st
 		}
 	case "array":
 		if it, ok := node["items"].(map[string]any); ok {
-			c.collectPaths(it, base, out)
+			c.collectPaths(it, base, out, visited)
 		}
 	}
 }
 
-func (c *Cache) ListCRDs() [][2]string {
+// CRDInfo is one registered (apiVersion, kind) pair plus the label of the
+// SchemaSource that won ownership of it (see registerSchema), so a caller
+// can tell a layered-in override apart from the base CRD set.
+type CRDInfo struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Source     string `json:"source,omitempty"`
+}
+
+func (c *Cache) ListCRDs() []CRDInfo {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	out := make([][2]string, 0, len(c.fullSchema))
+	out := make([]CRDInfo, 0, len(c.fullSchema))
 	for k := range c.fullSchema {
 		parts := strings.SplitN(k, "|", 2)
-		out = append(out, [2]string{parts[0], parts[1]})
+		out = append(out, CRDInfo{APIVersion: parts[0], Kind: parts[1], Source: c.provenance[k].source})
 	}
 	sort.Slice(out, func(i, j int) bool {
-		if out[i][0] == out[j][0] {
-			return out[i][1] < out[j][1]
+		if out[i].APIVersion == out[j].APIVersion {
+			return out[i].Kind < out[j].Kind
 		}
-		return out[i][0] < out[j][0]
+		return out[i].APIVersion < out[j].APIVersion
 	})
 	return out
 }
@@ -406,6 +847,50 @@ func (c *Cache) GetSchema(api, kind string) map[string]any {
 	return cloneMap(c.fullSchema[joinKey(api, kind)])
 }
 
+// ResolvedSchema returns api/kind's schema with every "$ref" inlined
+// against the components captured at ingest time, for consumers that want a
+// flat definition instead of chasing refs themselves. GetSchema/fullSchema
+// keep the original, $ref-bearing form.
+func (c *Cache) ResolvedSchema(api, kind string) map[string]any {
+	schema := c.GetSchema(api, kind)
+	if schema == nil {
+		return nil
+	}
+	return c.resolveSchemaDeep(schema, map[string]bool{})
+}
+
+// resolveSchemaDeep recursively inlines every $ref reachable from node,
+// returning a new map/slice tree (node and c.components aren't mutated).
+// visited is the same recursion-stack cycle guard resolveSchema uses, kept
+// active for the whole of a $ref's expanded subtree via resolveSchema's done.
+func (c *Cache) resolveSchemaDeep(node map[string]any, visited map[string]bool) map[string]any {
+	node, done := c.resolveSchema(node, visited)
+	defer done()
+	if node == nil {
+		return nil
+	}
+	out := make(map[string]any, len(node))
+	for k, v := range node {
+		out[k] = c.resolveSchemaDeepValue(v, visited)
+	}
+	return out
+}
+
+func (c *Cache) resolveSchemaDeepValue(v any, visited map[string]bool) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		return c.resolveSchemaDeep(vv, visited)
+	case []any:
+		out := make([]any, len(vv))
+		for i, e := range vv {
+			out[i] = c.resolveSchemaDeepValue(e, visited)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
 func (c *Cache) TopAllowed(api, kind string) map[string]struct{} {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -449,8 +934,10 @@ func (c *Cache) Skeleton(api, kind string) (string, error) {
 	props, _ := schema["properties"].(map[string]any)
 
 	// IMPORTANT: recursive closure must be declared then assigned
-	var build func(map[string]any) any
-	build = func(node map[string]any) any {
+	var build func(map[string]any, map[string]bool) any
+	build = func(node map[string]any, visited map[string]bool) any {
+		node, done := c.resolveSchema(node, visited)
+		defer done()
 		if node == nil {
 			return nil
 		}
@@ -463,7 +950,7 @@ func (c *Cache) Skeleton(api, kind string) (string, error) {
 				for k, sub := range pr {
 					if _, needed := reqset[k]; needed {
 						if sm, ok := sub.(map[string]any); ok {
-							out[k] = build(sm)
+							out[k] = build(sm, visited)
 						} else {
 							out[k] = nil
 						}
@@ -473,7 +960,7 @@ func (c *Cache) Skeleton(api, kind string) (string, error) {
 			return out
 		case "array":
 			if it, ok := node["items"].(map[string]any); ok {
-				return []any{build(it)}
+				return []any{build(it, visited)}
 			}
 			return []any{nil}
 		default:
@@ -501,7 +988,7 @@ func (c *Cache) Skeleton(api, kind string) (string, error) {
 	}
 
 	if sp, ok := props["spec"].(map[string]any); ok {
-		if x := build(sp); x != nil {
+		if x := build(sp, map[string]bool{}); x != nil {
 			if m, ok := x.(map[string]any); ok && len(m) == 0 {
 				body["spec"] = map[string]any{}
 			} else {
@@ -517,7 +1004,7 @@ func (c *Cache) Skeleton(api, kind string) (string, error) {
 		delete(reqTop, "metadata")
 		for k := range reqTop {
 			if pr, ok := props[k].(map[string]any); ok {
-				body[k] = build(pr)
+				body[k] = build(pr, map[string]bool{})
 			} else {
 				body[k] = nil
 			}
@@ -0,0 +1,93 @@
+package discovery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// diskEntry is one cached HTTP response body plus the validators needed to
+// revalidate it, keyed by the request URL. Stored as its own file under
+// diskDir so a restart doesn't lose the cache.
+type diskEntry struct {
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+}
+
+// diskMeta records the commit SHA a set of fetched files came from, so
+// refreshGitHubSource can recognize "the ref hasn't advanced" from a single
+// small request and skip the directory walk entirely instead of relying on
+// each file's own ETag to come back 304. Stored per SchemaSource (keyed by
+// its label), since each source has its own independent revision.
+type diskMeta struct {
+	SourceRevision string   `json:"source_revision"`
+	Files          []string `json:"files"`
+}
+
+// diskDir returns CacheDir, defaulting to a per-user cache directory.
+func (c *Cache) diskDir() string {
+	if c.CacheDir != "" {
+		return c.CacheDir
+	}
+	if d, err := os.UserCacheDir(); err == nil && d != "" {
+		return filepath.Join(d, "datum-mcp", "discovery")
+	}
+	return ".datum-mcp-cache"
+}
+
+// diskCacheKey maps a URL to a filesystem-safe cache file name.
+func diskCacheKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) loadDiskEntry(url string) (*diskEntry, bool) {
+	raw, err := os.ReadFile(filepath.Join(c.diskDir(), diskCacheKey(url)+".json"))
+	if err != nil {
+		return nil, false
+	}
+	var e diskEntry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+func (c *Cache) storeDiskEntry(url string, e *diskEntry) {
+	dir := c.diskDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return
+	}
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(dir, diskCacheKey(url)+".json"), raw, 0o600)
+}
+
+func (c *Cache) loadDiskMetaFor(label string) diskMeta {
+	var m diskMeta
+	raw, err := os.ReadFile(filepath.Join(c.diskDir(), "meta-"+diskCacheKey(label)+".json"))
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(raw, &m)
+	return m
+}
+
+func (c *Cache) storeDiskMetaFor(label string, m diskMeta) {
+	dir := c.diskDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(dir, "meta-"+diskCacheKey(label)+".json"), raw, 0o600)
+}
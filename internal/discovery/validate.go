@@ -0,0 +1,340 @@
+package discovery
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ValidationError is one structural or value violation found by Validate,
+// anchored to the offending field by a JSON Pointer (RFC 6901) path rooted
+// at the object (e.g. "/spec/ports/0/port").
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string { return fmt.Sprintf("%s: %s", e.Path, e.Message) }
+
+// Validate walks the stored OpenAPI schema for api/kind against obj and
+// returns every structural/value violation: wrong type, missing required
+// field, enum/pattern/range mismatch, and so on. It complements rather than
+// replaces AllowedSpec-based pruning: pruning decides which fields a
+// manifest is allowed to set at all, Validate checks that the fields it does
+// set satisfy the schema's own constraints, so apply-style callers can
+// reject a bad payload with a concrete reason instead of a generic API
+// server rejection.
+//
+// x-kubernetes-validations CEL rules are reported as informational errors
+// (there's no CEL runtime in this package to evaluate them against) rather
+// than silently ignored, so a caller at least knows the cluster will check
+// more than Validate did.
+func (c *Cache) Validate(api, kind string, obj map[string]any) []ValidationError {
+	schema := c.GetSchema(api, kind)
+	if schema == nil {
+		return []ValidationError{{Path: "", Message: fmt.Sprintf("unknown apiVersion/kind %s/%s", api, kind)}}
+	}
+	v := &validator{c: c}
+	v.walk("", schema, any(obj), map[string]bool{})
+	sort.Slice(v.errs, func(i, j int) bool { return v.errs[i].Path < v.errs[j].Path })
+	return v.errs
+}
+
+type validator struct {
+	c    *Cache
+	errs []ValidationError
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// walk validates value against schema at path (a JSON Pointer), resolving
+// $ref and allOf/anyOf/oneOf/not along the way.
+func (v *validator) walk(path string, schema map[string]any, value any, visited map[string]bool) {
+	schema, done := v.c.resolveSchema(schema, visited)
+	defer done()
+	if schema == nil || value == nil {
+		// A null/absent value is left to the enclosing object's "required"
+		// check rather than flagged here; most CRDs don't model nullable
+		// explicitly, so treating "absent" as "not yet set" avoids false
+		// positives on every optional field.
+		return
+	}
+
+	for _, sub := range asSchemaList(schema["allOf"]) {
+		v.walk(path, sub, value, visited)
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		subs := asSchemaList(schema[key])
+		if len(subs) == 0 {
+			continue
+		}
+		matched := 0
+		for _, sub := range subs {
+			probe := &validator{c: v.c}
+			probe.walk(path, sub, value, visited)
+			if len(probe.errs) == 0 {
+				matched++
+			}
+		}
+		switch {
+		case matched == 0:
+			v.fail(path, "does not satisfy any of %d %s branches", len(subs), key)
+		case key == "oneOf" && matched > 1:
+			v.fail(path, "matches %d oneOf branches, want exactly 1", matched)
+		}
+	}
+	if notSchema, ok := schema["not"].(map[string]any); ok {
+		probe := &validator{c: v.c}
+		probe.walk(path, notSchema, value, visited)
+		if len(probe.errs) == 0 {
+			v.fail(path, "must not match the \"not\" schema")
+		}
+	}
+
+	for _, r := range asList(schema["x-kubernetes-validations"]) {
+		rm, _ := r.(map[string]any)
+		rule, _ := rm["rule"].(string)
+		if rule == "" {
+			continue
+		}
+		msg, _ := rm["message"].(string)
+		if msg == "" {
+			msg = rule
+		}
+		v.fail(path, "x-kubernetes-validations rule not evaluated locally, cluster will enforce: %s", msg)
+	}
+
+	if b, ok := schema["x-kubernetes-int-or-string"].(bool); ok && b {
+		switch value.(type) {
+		case float64, int, int64, string:
+		default:
+			v.fail(path, "must be an int or string (x-kubernetes-int-or-string), got %s", typeName(value))
+		}
+		return
+	}
+
+	if typ, _ := schema["type"].(string); typ != "" {
+		switch typ {
+		case "object":
+			v.walkObject(path, schema, value, visited)
+		case "array":
+			v.walkArray(path, schema, value, visited)
+		case "string":
+			v.walkString(path, schema, value)
+		case "integer", "number":
+			v.walkNumber(path, schema, typ, value)
+		case "boolean":
+			if _, ok := value.(bool); !ok {
+				v.fail(path, "must be a boolean, got %s", typeName(value))
+			}
+		default:
+			v.fail(path, "unsupported schema type %q", typ)
+		}
+	}
+
+	if enum := asList(schema["enum"]); len(enum) > 0 {
+		ok := false
+		for _, e := range enum {
+			if jsonEqual(e, value) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			v.fail(path, "must be one of %v", enum)
+		}
+	}
+}
+
+func (v *validator) walkObject(path string, schema map[string]any, value any, visited map[string]bool) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		v.fail(path, "must be an object, got %s", typeName(value))
+		return
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	for _, req := range asList(schema["required"]) {
+		name, _ := req.(string)
+		if name == "" {
+			continue
+		}
+		if _, present := obj[name]; !present {
+			v.fail(joinPointer(path, name), "is required")
+		}
+	}
+
+	preserveUnknown, _ := schema["x-kubernetes-preserve-unknown-fields"].(bool)
+	additional, hasAdditional := schema["additionalProperties"]
+
+	for name, fieldVal := range obj {
+		childPath := joinPointer(path, name)
+		if propSchema, ok := props[name].(map[string]any); ok {
+			v.walk(childPath, propSchema, fieldVal, visited)
+			continue
+		}
+		if preserveUnknown {
+			continue
+		}
+		switch ap := additional.(type) {
+		case map[string]any:
+			v.walk(childPath, ap, fieldVal, visited)
+		case bool:
+			if !ap && hasAdditional {
+				v.fail(childPath, "unknown field")
+			}
+		default:
+			if props != nil {
+				// properties was declared but this field isn't in it, and
+				// additionalProperties said nothing either way: Kubernetes
+				// structural schemas treat that as disallowed.
+				v.fail(childPath, "unknown field")
+			}
+		}
+	}
+}
+
+func (v *validator) walkArray(path string, schema map[string]any, value any, visited map[string]bool) {
+	arr, ok := value.([]any)
+	if !ok {
+		v.fail(path, "must be an array, got %s", typeName(value))
+		return
+	}
+	items, _ := schema["items"].(map[string]any)
+	if items == nil {
+		return
+	}
+	for i, el := range arr {
+		v.walk(fmt.Sprintf("%s/%d", path, i), items, el, visited)
+	}
+}
+
+func (v *validator) walkString(path string, schema map[string]any, value any) {
+	s, ok := value.(string)
+	if !ok {
+		v.fail(path, "must be a string, got %s", typeName(value))
+		return
+	}
+	if n, ok := asInt(schema["minLength"]); ok && len(s) < n {
+		v.fail(path, "length %d is less than minLength %d", len(s), n)
+	}
+	if n, ok := asInt(schema["maxLength"]); ok && len(s) > n {
+		v.fail(path, "length %d is greater than maxLength %d", len(s), n)
+	}
+	if pat, _ := schema["pattern"].(string); pat != "" {
+		re, err := regexp.Compile(pat)
+		if err != nil {
+			return
+		}
+		if !re.MatchString(s) {
+			v.fail(path, "does not match pattern %q", pat)
+		}
+	}
+}
+
+func (v *validator) walkNumber(path string, schema map[string]any, typ string, value any) {
+	n, ok := asFloat(value)
+	if !ok {
+		v.fail(path, "must be a %s, got %s", typ, typeName(value))
+		return
+	}
+	if typ == "integer" && n != float64(int64(n)) {
+		v.fail(path, "must be an integer, got %v", value)
+	}
+	if min, ok := asFloat(schema["minimum"]); ok && n < min {
+		v.fail(path, "%v is less than minimum %v", value, min)
+	}
+	if max, ok := asFloat(schema["maximum"]); ok && n > max {
+		v.fail(path, "%v is greater than maximum %v", value, max)
+	}
+}
+
+func asSchemaList(v any) []map[string]any {
+	var out []map[string]any
+	for _, e := range asList(v) {
+		if m, ok := e.(map[string]any); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func asList(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
+
+func asInt(v any) (int, bool) {
+	f, ok := asFloat(v)
+	if !ok {
+		return 0, false
+	}
+	return int(f), true
+}
+
+func asFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int64:
+		return "number"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// jsonEqual compares two values decoded from JSON/YAML, treating any
+// numeric pairing as equal by value regardless of Go type (YAML decodes
+// "1" as int, JSON decodes it as float64, and an enum's values come from
+// whichever of those produced the schema).
+func jsonEqual(a, b any) bool {
+	if af, aok := asFloat(a); aok {
+		if bf, bok := asFloat(b); bok {
+			return af == bf
+		}
+	}
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if aok && bok {
+		return as == bs
+	}
+	ab, aok := a.(bool)
+	bb, bok := b.(bool)
+	if aok && bok {
+		return ab == bb
+	}
+	return false
+}
+
+// joinPointer appends name as a new RFC 6901 JSON Pointer segment, escaping
+// "~" and "/" within it.
+func joinPointer(base, name string) string {
+	name = strings.ReplaceAll(name, "~", "~0")
+	name = strings.ReplaceAll(name, "/", "~1")
+	return base + "/" + name
+}
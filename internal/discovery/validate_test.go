@@ -0,0 +1,212 @@
+package discovery
+
+import "testing"
+
+// newTestCache returns a Cache with one api/kind registered under schema,
+// plus any named components (for $ref resolution), ready for Validate.
+func newTestCache(t *testing.T, api, kind string, schema map[string]any, components map[string]any) *Cache {
+	t.Helper()
+	c := New()
+	for name, s := range components {
+		sm, ok := s.(map[string]any)
+		if !ok {
+			continue
+		}
+		c.compProvenance[name] = schemaProvenance{source: "test", priority: 0}
+		c.components[name] = sm
+	}
+	c.registerSchema("test", 0, api, kind, schema)
+	return c
+}
+
+func TestValidate_UnknownKind(t *testing.T) {
+	c := New()
+	errs := c.Validate("foo/v1", "Bar", map[string]any{})
+	if len(errs) != 1 {
+		t.Fatalf("want 1 error, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Path != "" {
+		t.Errorf("want root path, got %q", errs[0].Path)
+	}
+}
+
+func TestValidate_Object(t *testing.T) {
+	schema := map[string]any{
+		"type":     "object",
+		"required": []any{"name"},
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string", "minLength": float64(1)},
+			"port": map[string]any{"type": "integer", "minimum": float64(1), "maximum": float64(65535)},
+		},
+	}
+	c := newTestCache(t, "v1", "Widget", schema, nil)
+
+	tests := []struct {
+		name    string
+		obj     map[string]any
+		wantErr []string
+	}{
+		{
+			name: "valid",
+			obj:  map[string]any{"name": "x", "port": float64(80)},
+		},
+		{
+			name:    "missing required field",
+			obj:     map[string]any{"port": float64(80)},
+			wantErr: []string{"/name"},
+		},
+		{
+			name:    "wrong type",
+			obj:     map[string]any{"name": "x", "port": "not-a-number"},
+			wantErr: []string{"/port"},
+		},
+		{
+			name:    "out of range",
+			obj:     map[string]any{"name": "x", "port": float64(99999)},
+			wantErr: []string{"/port"},
+		},
+		{
+			name:    "unknown field rejected",
+			obj:     map[string]any{"name": "x", "bogus": "y"},
+			wantErr: []string{"/bogus"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := c.Validate("v1", "Widget", tt.obj)
+			assertPaths(t, errs, tt.wantErr)
+		})
+	}
+}
+
+func TestValidate_ArrayAndString(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"tags": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "string", "pattern": "^[a-z]+$"},
+			},
+		},
+	}
+	c := newTestCache(t, "v1", "Widget", schema, nil)
+
+	errs := c.Validate("v1", "Widget", map[string]any{"tags": []any{"ok", "Bad1"}})
+	assertPaths(t, errs, []string{"/tags/1"})
+}
+
+func TestValidate_EnumAndPreserveUnknown(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"phase": map[string]any{"type": "string", "enum": []any{"Pending", "Ready"}},
+			"meta":  map[string]any{"type": "object", "x-kubernetes-preserve-unknown-fields": true},
+		},
+	}
+	c := newTestCache(t, "v1", "Widget", schema, nil)
+
+	errs := c.Validate("v1", "Widget", map[string]any{
+		"phase": "Unknown",
+		"meta":  map[string]any{"anything": "goes"},
+	})
+	assertPaths(t, errs, []string{"/phase"})
+}
+
+func TestValidate_Ref(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"metadata": map[string]any{"$ref": "#/components/schemas/ObjectMeta"},
+		},
+	}
+	components := map[string]any{
+		"ObjectMeta": map[string]any{
+			"type":     "object",
+			"required": []any{"name"},
+			"properties": map[string]any{
+				"name": map[string]any{"type": "string"},
+			},
+		},
+	}
+	c := newTestCache(t, "v1", "Widget", schema, components)
+
+	errs := c.Validate("v1", "Widget", map[string]any{"metadata": map[string]any{}})
+	assertPaths(t, errs, []string{"/metadata/name"})
+}
+
+func TestValidate_OneOfAndNot(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"value": map[string]any{
+				"oneOf": []any{
+					map[string]any{"type": "string"},
+					map[string]any{"type": "integer"},
+				},
+			},
+		},
+	}
+	c := newTestCache(t, "v1", "Widget", schema, nil)
+	if errs := c.Validate("v1", "Widget", map[string]any{"value": "x"}); len(errs) != 0 {
+		t.Errorf("string should satisfy exactly one branch, got %v", errs)
+	}
+
+	notSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"value": map[string]any{"not": map[string]any{"type": "string"}},
+		},
+	}
+	c2 := newTestCache(t, "v1", "Gadget", notSchema, nil)
+	if errs := c2.Validate("v1", "Gadget", map[string]any{"value": "x"}); len(errs) == 0 {
+		t.Errorf("string should fail \"not\" of string schema")
+	}
+	if errs := c2.Validate("v1", "Gadget", map[string]any{"value": float64(1)}); len(errs) != 0 {
+		t.Errorf("number should satisfy \"not\" of string schema, got %v", errs)
+	}
+}
+
+func TestValidate_IntOrStringAndCELInformational(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"port": map[string]any{"x-kubernetes-int-or-string": true},
+		},
+		"x-kubernetes-validations": []any{
+			map[string]any{"rule": "self.port > 0", "message": "port must be positive"},
+		},
+	}
+	c := newTestCache(t, "v1", "Widget", schema, nil)
+
+	errs := c.Validate("v1", "Widget", map[string]any{"port": "8080"})
+	foundInformational := false
+	for _, e := range errs {
+		if e.Path == "" && e.Message != "" {
+			foundInformational = true
+		}
+	}
+	if !foundInformational {
+		t.Errorf("want an informational x-kubernetes-validations error at root, got %v", errs)
+	}
+
+	errs = c.Validate("v1", "Widget", map[string]any{"port": true})
+	assertPaths(t, errs, []string{"/port", ""})
+}
+
+// assertPaths checks that errs contains exactly the given set of paths
+// (order-independent; the caller does not care about message text).
+func assertPaths(t *testing.T, errs []ValidationError, want []string) {
+	t.Helper()
+	if len(errs) != len(want) {
+		t.Fatalf("want %d error(s) at %v, got %d: %v", len(want), want, len(errs), errs)
+	}
+	seen := map[string]bool{}
+	for _, e := range errs {
+		seen[e.Path] = true
+	}
+	for _, p := range want {
+		if !seen[p] {
+			t.Errorf("want error at path %q, got %v", p, errs)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package environment
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/datum-cloud/datum-mcp/internal/authutil"
+	"github.com/datum-cloud/datum-mcp/internal/keyring"
+)
+
+const activeEnvironmentKey = "active_environment"
+
+func GetActive() (string, error) {
+	e, err := keyring.Get(authutil.ServiceName, activeEnvironmentKey)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read active environment: %w", err)
+	}
+	return e, nil
+}
+
+func SetActive(name string) error {
+	if name == "" {
+		return fmt.Errorf("environment name cannot be empty")
+	}
+	return keyring.Set(authutil.ServiceName, activeEnvironmentKey, name)
+}
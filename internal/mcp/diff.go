@@ -0,0 +1,280 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/datum-cloud/datum-mcp/internal/api"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+type DiffReq struct {
+	YAML string `json:"yaml"`
+	// Project selects the control-plane project to diff against; falls back
+	// to the active project (see the `projects` tool) when empty.
+	Project string `json:"project,omitempty"`
+	// FieldManager is the SSA field manager to dry-run apply as; defaults to
+	// defaultFieldManager when empty.
+	FieldManager string `json:"fieldManager,omitempty"`
+}
+
+type DiffResp struct {
+	// Diff is a unified diff between the live object and the server-side
+	// apply dry-run result; empty when there would be no change.
+	Diff string `json:"diff"`
+	// Changed is true when Diff is non-empty.
+	Changed bool `json:"changed"`
+	// ManagedFieldsOwners lists "<manager>/<operation>" entries whose
+	// managed fields would change (added, removed, or altered ownership).
+	ManagedFieldsOwners []string `json:"managedFieldsOwners,omitempty"`
+	// Conflicts lists Server-Side Apply ownership conflicts reported for
+	// the dry-run, if any; Diff is empty when conflicts are present since
+	// the apply itself was rejected.
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// Diff performs a Server-Side Apply dry-run (PATCH ...?dryRun=All with
+// Content-Type: application/apply-patch+yaml) and returns a unified diff
+// between the live object and the dry-run result, analogous to `kubectl
+// diff`. A not-found live object diffs against an empty document (i.e. the
+// whole manifest shows as additions).
+func (s *Service) Diff(ctx context.Context, r DiffReq) (DiffResp, error) {
+	var m map[string]any
+	if err := yaml.Unmarshal([]byte(r.YAML), &m); err != nil {
+		return DiffResp{}, fmt.Errorf("Invalid YAML: %w", err)
+	}
+	apiVersion, _ := m["apiVersion"].(string)
+	kind, _ := m["kind"].(string)
+	if !s.Disc.Has(apiVersion, kind) {
+		return DiffResp{}, fmt.Errorf("%s/%s is not known to the control plane", apiVersion, kind)
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return DiffResp{}, fmt.Errorf("invalid apiVersion %q: %w", apiVersion, err)
+	}
+	meta, _ := m["metadata"].(map[string]any)
+	name, _ := meta["name"].(string)
+	if name == "" {
+		return DiffResp{}, fmt.Errorf("metadata.name is required")
+	}
+	namespace, _ := meta["namespace"].(string)
+
+	cli, err := s.controlPlaneClient(ctx, r.Project)
+	if err != nil {
+		return DiffResp{}, err
+	}
+
+	live, err := api.FetchObject(ctx, cli, gv.Group, kind, namespace, name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return DiffResp{}, err
+	}
+
+	fm := r.FieldManager
+	if fm == "" {
+		fm = defaultFieldManager
+	}
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return DiffResp{}, err
+	}
+	result, err := api.PatchObject(ctx, cli, gv.Group, kind, namespace, name, types.ApplyPatchType, payload, api.PatchOptions{
+		FieldManager: fm,
+		DryRun:       true,
+	})
+	if err != nil {
+		if api.IsConflict(err) {
+			return DiffResp{Conflicts: conflictCauses(err)}, nil
+		}
+		return DiffResp{}, err
+	}
+
+	liveText, err := renderForDiff(live)
+	if err != nil {
+		return DiffResp{}, err
+	}
+	resultText, err := renderForDiff(result)
+	if err != nil {
+		return DiffResp{}, err
+	}
+	diff := unifiedDiff(fmt.Sprintf("live/%s", name), fmt.Sprintf("dry-run/%s", name), liveText, resultText)
+	return DiffResp{
+		Diff:                diff,
+		Changed:             diff != "",
+		ManagedFieldsOwners: diffManagedFieldsOwners(live, result),
+	}, nil
+}
+
+// renderForDiff marshals obj (nil for a not-yet-existing live object) as
+// pretty-printed YAML for a stable, human-readable diff.
+func renderForDiff(obj *unstructured.Unstructured) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	b, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// conflictCauses extracts the human-readable Server-Side Apply ownership
+// conflict messages (field path + competing manager) from a *ConflictError.
+func conflictCauses(err error) []string {
+	status, ok := api.StatusErrorDetails(err)
+	if !ok || status.Details == nil {
+		return []string{err.Error()}
+	}
+	causes := make([]string, 0, len(status.Details.Causes))
+	for _, c := range status.Details.Causes {
+		if c.Field != "" {
+			causes = append(causes, fmt.Sprintf("%s: %s", c.Field, c.Message))
+		} else {
+			causes = append(causes, c.Message)
+		}
+	}
+	if len(causes) == 0 {
+		return []string{status.Message}
+	}
+	return causes
+}
+
+// managedFieldsSignatures keys each managedFields entry by "<manager>/
+// <operation>" and values it by its FieldsV1 payload, so two objects' field
+// ownership can be compared with a plain map diff.
+func managedFieldsSignatures(obj *unstructured.Unstructured) map[string]string {
+	out := map[string]string{}
+	if obj == nil {
+		return out
+	}
+	for _, mf := range obj.GetManagedFields() {
+		var fields any
+		if mf.FieldsV1 != nil {
+			_ = json.Unmarshal(mf.FieldsV1.Raw, &fields)
+		}
+		b, _ := json.Marshal(fields)
+		out[mf.Manager+"/"+string(mf.Operation)] = string(b)
+	}
+	return out
+}
+
+// diffManagedFieldsOwners reports which "<manager>/<operation>" entries
+// differ (added, removed, or changed field ownership) between before and
+// after, sorted for stable output.
+func diffManagedFieldsOwners(before, after *unstructured.Unstructured) []string {
+	b := managedFieldsSignatures(before)
+	a := managedFieldsSignatures(after)
+	seen := make(map[string]bool, len(a))
+	var changed []string
+	for k, av := range a {
+		seen[k] = true
+		if bv, ok := b[k]; !ok || bv != av {
+			changed = append(changed, k)
+		}
+	}
+	for k := range b {
+		if !seen[k] {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// unifiedDiff produces a standard unified diff between aText and bText.
+// Unlike `diff -u`, context lines are never collapsed: manifests are small
+// enough that one full hunk is easier for an LLM client to reason about
+// than trimmed context windows. Returns "" when aText and bText are
+// identical.
+func unifiedDiff(aName, bName, aText, bText string) string {
+	aLines := splitLines(aText)
+	bLines := splitLines(bText)
+	ops := diffLines(aLines, bLines)
+
+	var body strings.Builder
+	changed := false
+	for _, op := range ops {
+		switch op.tag {
+		case ' ':
+			fmt.Fprintf(&body, " %s\n", op.line)
+		case '-':
+			changed = true
+			fmt.Fprintf(&body, "-%s\n", op.line)
+		case '+':
+			changed = true
+			fmt.Fprintf(&body, "+%s\n", op.line)
+		}
+	}
+	if !changed {
+		return ""
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n@@ -1,%d +1,%d @@\n", aName, bName, len(aLines), len(bLines))
+	out.WriteString(body.String())
+	return out.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+type diffOp struct {
+	tag  byte
+	line string
+}
+
+// diffLines is a textbook LCS-based line diff (O(n*m) time/space), adequate
+// for the modest manifest sizes datum_diff_crd operates on.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		empty bool
+	}{
+		{name: "identical", a: "x\ny\n", b: "x\ny\n", empty: true},
+		{name: "both empty", a: "", b: "", empty: true},
+		{name: "changed", a: "x\ny\n", b: "x\nz\n", empty: false},
+		{name: "additions only", a: "", b: "x\ny\n", empty: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unifiedDiff("live/foo", "dry-run/foo", tt.a, tt.b)
+			if (got == "") != tt.empty {
+				t.Fatalf("unifiedDiff(%q, %q) = %q, want empty=%v", tt.a, tt.b, got, tt.empty)
+			}
+			if !tt.empty {
+				if want := "--- live/foo\n+++ dry-run/foo\n"; got[:len(want)] != want {
+					t.Errorf("missing unified diff header, got %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff_LineChanges(t *testing.T) {
+	got := unifiedDiff("a", "b", "one\ntwo\nthree\n", "one\nTWO\nthree\n")
+	want := "--- a\n+++ b\n@@ -1,3 +1,3 @@\n one\n-two\n+TWO\n three\n"
+	if got != want {
+		t.Errorf("unifiedDiff mismatch:\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	ops := diffLines([]string{"a", "b", "c"}, []string{"a", "x", "c"})
+	want := []diffOp{
+		{tag: ' ', line: "a"},
+		{tag: '-', line: "b"},
+		{tag: '+', line: "x"},
+		{tag: ' ', line: "c"},
+	}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("diffLines = %+v, want %+v", ops, want)
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	if got := splitLines(""); got != nil {
+		t.Errorf("splitLines(\"\") = %v, want nil", got)
+	}
+	if got, want := splitLines("a\nb\n"), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitLines = %v, want %v", got, want)
+	}
+	if got, want := splitLines("a\nb"), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitLines (no trailing newline) = %v, want %v", got, want)
+	}
+}
+
+func managedObj(entries ...metav1.ManagedFieldsEntry) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{}}
+	u.SetManagedFields(entries)
+	return u
+}
+
+func TestManagedFieldsSignatures(t *testing.T) {
+	if got := managedFieldsSignatures(nil); len(got) != 0 {
+		t.Errorf("nil object should yield no signatures, got %v", got)
+	}
+
+	u := managedObj(metav1.ManagedFieldsEntry{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply})
+	got := managedFieldsSignatures(u)
+	if _, ok := got["kubectl/Apply"]; !ok {
+		t.Errorf("want signature for kubectl/Apply, got %v", got)
+	}
+}
+
+func TestDiffManagedFieldsOwners(t *testing.T) {
+	before := managedObj(metav1.ManagedFieldsEntry{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply})
+	after := managedObj(
+		metav1.ManagedFieldsEntry{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply},
+		metav1.ManagedFieldsEntry{Manager: "controller", Operation: metav1.ManagedFieldsOperationUpdate},
+	)
+
+	got := diffManagedFieldsOwners(before, after)
+	want := []string{"controller/Update"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffManagedFieldsOwners = %v, want %v", got, want)
+	}
+
+	if got := diffManagedFieldsOwners(before, before); len(got) != 0 {
+		t.Errorf("identical managed fields should report no changed owners, got %v", got)
+	}
+}
+
+func TestConflictCauses(t *testing.T) {
+	gr := schema.GroupResource{Group: "example.com", Resource: "widgets"}
+	statusErr := apierrors.NewConflict(gr, "foo", nil)
+	statusErr.ErrStatus.Details = &metav1.StatusDetails{
+		Causes: []metav1.StatusCause{
+			{Field: "spec.replicas", Message: "conflict with controller"},
+			{Message: "generic cause with no field"},
+		},
+	}
+
+	got := conflictCauses(statusErr)
+	want := []string{"spec.replicas: conflict with controller", "generic cause with no field"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("conflictCauses = %v, want %v", got, want)
+	}
+}
+
+func TestConflictCauses_NonStatusError(t *testing.T) {
+	got := conflictCauses(errTest("boom"))
+	want := []string{"boom"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("conflictCauses(non-status error) = %v, want %v", got, want)
+	}
+}
+
+type errTest string
+
+func (e errTest) Error() string { return string(e) }
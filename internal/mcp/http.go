@@ -4,11 +4,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/datum-cloud/datum-mcp/internal/api"
 )
 
 func ServeHTTP(s *Service, port int) error {
 	mux := http.NewServeMux()
 
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprint(w, api.DefaultMetrics.WriteProm())
+	})
+
 	mux.HandleFunc("/datum/list_crds", func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, s.ListCRDs())
 	})
@@ -47,7 +54,7 @@ func ServeHTTP(s *Service, port int) error {
 			http.Error(w, "bad request", 400)
 			return
 		}
-		_, err := s.Prune(req)
+		resp, err := s.Prune(r.Context(), req)
 		if err != nil {
 			if bad, _ := IsUnsupportedRemoved(err); bad {
 				http.Error(w, err.Error(), 422)
@@ -56,7 +63,6 @@ func ServeHTTP(s *Service, port int) error {
 			http.Error(w, err.Error(), 400)
 			return
 		}
-		resp, _ := s.Prune(req) // safe: nothing removed
 		writeJSON(w, resp)
 	})
 
@@ -66,7 +72,88 @@ func ServeHTTP(s *Service, port int) error {
 			http.Error(w, "bad request", 400)
 			return
 		}
-		writeJSON(w, s.Validate(req))
+		writeJSON(w, s.Validate(r.Context(), req))
+	})
+
+	mux.HandleFunc("/datum/apply_crd", func(w http.ResponseWriter, r *http.Request) {
+		var req ApplyReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", 400)
+			return
+		}
+		resp, err := s.Apply(r.Context(), req)
+		if err != nil {
+			if bad, _ := IsUnsupportedRemoved(err); bad {
+				http.Error(w, err.Error(), 422)
+				return
+			}
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		writeJSON(w, resp)
+	})
+
+	mux.HandleFunc("/datum/diff_crd", func(w http.ResponseWriter, r *http.Request) {
+		var req DiffReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", 400)
+			return
+		}
+		resp, err := s.Diff(r.Context(), req)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		writeJSON(w, resp)
+	})
+
+	mux.HandleFunc("/datum/get_schema", func(w http.ResponseWriter, r *http.Request) {
+		var req SchemaReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", 400)
+			return
+		}
+		resp, err := s.GetSchema(req)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		writeJSON(w, resp)
+	})
+
+	mux.HandleFunc("/datum/resources", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.ListResources())
+	})
+
+	mux.HandleFunc("/datum/resources/read", func(w http.ResponseWriter, r *http.Request) {
+		uri := r.URL.Query().Get("uri")
+		resp, err := s.ReadResource(uri)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		writeJSON(w, resp)
+	})
+
+	mux.HandleFunc("/datum/prompts", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.ListPrompts())
+	})
+
+	mux.HandleFunc("/datum/prompts/get", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name      string            `json:"name"`
+			Arguments map[string]string `json:"arguments"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", 400)
+			return
+		}
+		msgs, err := s.GetPrompt(req.Name, req.Arguments)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		writeJSON(w, map[string]any{"messages": msgs})
 	})
 
 	mux.HandleFunc("/datum/refresh_discovery", func(w http.ResponseWriter, r *http.Request) {
@@ -78,6 +165,10 @@ func ServeHTTP(s *Service, port int) error {
 		writeJSON(w, map[string]any{"ok": ok, "count": count})
 	})
 
+	mux.HandleFunc("/datum/cache_stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.CacheStats())
+	})
+
 	addr := fmt.Sprintf("127.0.0.1:%d", port)
 	return http.ListenAndServe(addr, mux)
 }
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/datum-cloud/datum-mcp/internal/api"
+	"github.com/datum-cloud/datum-mcp/internal/watcher"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// liveResourcePrefix is the URI scheme for project-scoped, informer-backed
+// live resources, distinct from the static datum://crd/ schema resources in
+// resources.go: datum://project/{project}/{group}/{version}/{kind}, group
+// "core" for no-group APIs (mirroring resourceURI), with optional
+// ?namespace=&labelSelector=&fieldSelector= query parameters.
+const liveResourcePrefix = "datum://project/"
+
+// parseLiveResourceURI parses a datum://project/... URI into the GVR and
+// selector Options an MCP client constructs directly from the uriTemplate
+// in ListResourceTemplates.
+func parseLiveResourceURI(uri string) (watcher.GVR, watcher.Options, bool) {
+	if !strings.HasPrefix(uri, liveResourcePrefix) {
+		return watcher.GVR{}, watcher.Options{}, false
+	}
+	rest := strings.TrimPrefix(uri, liveResourcePrefix)
+	path, query, _ := strings.Cut(rest, "?")
+	parts := strings.Split(path, "/")
+	if len(parts) != 4 {
+		return watcher.GVR{}, watcher.Options{}, false
+	}
+	project, group, version, kind := parts[0], parts[1], parts[2], parts[3]
+	if group == "core" {
+		group = ""
+	}
+	gvr := watcher.GVR{Project: project, Group: group, Version: version, Kind: kind}
+	var opts watcher.Options
+	if query != "" {
+		if q, err := url.ParseQuery(query); err == nil {
+			gvr.Namespace = q.Get("namespace")
+			opts.LabelSelector = q.Get("labelSelector")
+			opts.FieldSelector = q.Get("fieldSelector")
+		}
+	}
+	return gvr, opts, true
+}
+
+// liveResourceClient adapts api.NewProjectControlPlaneClient to
+// watcher.ClientFactory.
+func liveResourceClient(ctx context.Context, project string) (ctrlclient.Client, error) {
+	return api.NewProjectControlPlaneClient(ctx, project, nil)
+}
+
+// liveResourceTemplate describes the liveResourcePrefix URI shape for
+// ListResourceTemplates, alongside the static datum://crd/ template in
+// resources.go.
+func liveResourceTemplate() map[string]any {
+	return map[string]any{
+		"uriTemplate": "datum://project/{project}/{group}/{version}/{kind}{?namespace,labelSelector,fieldSelector}",
+		"name":        "live-resource",
+		"description": "Informer-cached, live-updating list for a project-scoped group/version/kind (group \"core\" for no-group APIs). Supports resources/subscribe for change notifications.",
+		"mimeType":    "application/json",
+	}
+}
+
+// ReadLiveResource returns uri's current informer-cached list as JSON,
+// starting (and leaving running) the backing informer on first use so the
+// next read or subscribe doesn't repay its list/watch setup cost.
+func (s *Service) ReadLiveResource(ctx context.Context, uri string) (ResourceContents, error) {
+	gvr, opts, ok := parseLiveResourceURI(uri)
+	if !ok {
+		return ResourceContents{}, fmt.Errorf("unrecognized resource URI %q", uri)
+	}
+	_, items, _, err := s.Watch.Subscribe(ctx, liveResourceClient, gvr, opts)
+	if err != nil {
+		return ResourceContents{}, err
+	}
+	return renderLiveResource(uri, items), nil
+}
+
+// resourceNotifier is handleRPC's hook for resources/subscribe: it lets the
+// caller push notifications/resources/updated to whatever transport it's
+// on (a session's SSE stream, or stdout for STDIO) and track the
+// subscription's cancel func keyed by URI so resources/unsubscribe can tear
+// it down again.
+type resourceNotifier interface {
+	notify(method string, params map[string]any)
+	addSubscription(uri string, cancel func())
+	removeSubscription(uri string) (func(), bool)
+}
+
+// subscribeResource starts (or reuses) the informer behind uri and
+// registers rn to receive notifications/resources/updated on every
+// add/update/delete until rn.removeSubscription(uri) is called.
+func (s *Service) subscribeResource(ctx context.Context, rn resourceNotifier, uri string) error {
+	gvr, opts, ok := parseLiveResourceURI(uri)
+	if !ok {
+		return fmt.Errorf("unrecognized resource URI %q", uri)
+	}
+	events, _, unsubscribe, err := s.Watch.Subscribe(ctx, liveResourceClient, gvr, opts)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	rn.addSubscription(uri, func() {
+		close(done)
+		unsubscribe()
+	})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case _, ok := <-events:
+				if !ok {
+					return
+				}
+				rn.notify("notifications/resources/updated", map[string]any{"uri": uri})
+			}
+		}
+	}()
+	return nil
+}
+
+// unsubscribeResource tears down rn's subscription to uri, if any.
+func (s *Service) unsubscribeResource(rn resourceNotifier, uri string) {
+	if cancel, ok := rn.removeSubscription(uri); ok {
+		cancel()
+	}
+}
+
+// notifierCtxKey is the context key handleRPC's resources/subscribe and
+// resources/unsubscribe cases use to reach the calling transport's
+// resourceNotifier (the STDIO singleton or the current mcpSession), without
+// threading an extra parameter through every handleRPC call site.
+type notifierCtxKey struct{}
+
+// withNotifier attaches rn to ctx for the lifetime of one handleRPC call.
+func withNotifier(ctx context.Context, rn resourceNotifier) context.Context {
+	return context.WithValue(ctx, notifierCtxKey{}, rn)
+}
+
+// notifierFromContext retrieves the resourceNotifier attached by
+// withNotifier, if any.
+func notifierFromContext(ctx context.Context) (resourceNotifier, bool) {
+	rn, ok := ctx.Value(notifierCtxKey{}).(resourceNotifier)
+	return rn, ok
+}
+
+// renderLiveResource renders items as the JSON body of a resources/read
+// result for uri.
+func renderLiveResource(uri string, items []*unstructured.Unstructured) ResourceContents {
+	objs := make([]map[string]any, len(items))
+	for i, u := range items {
+		objs[i] = u.Object
+	}
+	b, err := json.MarshalIndent(objs, "", "  ")
+	if err != nil {
+		b, _ = json.Marshal(objs)
+	}
+	return ResourceContents{URI: uri, MimeType: "application/json", Text: string(b)}
+}
@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Resource is an MCP resources/list entry.
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents is an MCP resources/read result entry.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// Prompt is an MCP prompts/list entry.
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptMessage is an MCP prompts/get result message.
+type PromptMessage struct {
+	Role    string `json:"role"`
+	Content struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+// resourceURI builds the datum://crd/{group}/{version}/{kind} URI for a
+// discovered apiVersion/kind pair.
+func resourceURI(apiVersion, kind string) string {
+	group, version := splitAPIVersion(apiVersion)
+	if group == "" {
+		return fmt.Sprintf("datum://crd/core/%s/%s", version, kind)
+	}
+	return fmt.Sprintf("datum://crd/%s/%s/%s", group, version, kind)
+}
+
+// splitAPIVersion splits "group/version" into (group, version), or
+// ("", version) for core/v1-style apiVersions with no group.
+func splitAPIVersion(apiVersion string) (group, version string) {
+	if i := strings.LastIndex(apiVersion, "/"); i >= 0 {
+		return apiVersion[:i], apiVersion[i+1:]
+	}
+	return "", apiVersion
+}
+
+// parseResourceURI is the inverse of resourceURI.
+func parseResourceURI(uri string) (apiVersion, kind string, ok bool) {
+	const prefix = "datum://crd/"
+	if !strings.HasPrefix(uri, prefix) {
+		return "", "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(uri, prefix), "/")
+	if len(parts) != 3 {
+		return "", "", false
+	}
+	group, version, kind := parts[0], parts[1], parts[2]
+	if group == "core" {
+		return version, kind, true
+	}
+	return group + "/" + version, kind, true
+}
+
+// ListResources exposes every discovered apiVersion/kind pair as an MCP
+// resource, one per GVK, so clients can browse the control plane's schema
+// the same way they'd browse files.
+func (s *Service) ListResources() []Resource {
+	crds := s.Disc.ListCRDs()
+	out := make([]Resource, 0, len(crds))
+	for _, gk := range crds {
+		apiVersion, kind := gk.APIVersion, gk.Kind
+		out = append(out, Resource{
+			URI:         resourceURI(apiVersion, kind),
+			Name:        fmt.Sprintf("%s/%s", apiVersion, kind),
+			Description: fmt.Sprintf("Skeleton YAML and allowed fields for %s/%s", apiVersion, kind),
+			MimeType:    "text/yaml",
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].URI < out[j].URI })
+	return out
+}
+
+// ListResourceTemplates describes the datum://crd/{group}/{version}/{kind}
+// URI shape, plus the live datum://project/... shape (see
+// liveResourceTemplate), for clients that construct resource URIs themselves
+// rather than enumerating ListResources.
+func (s *Service) ListResourceTemplates() []map[string]any {
+	return []map[string]any{
+		{
+			"uriTemplate": "datum://crd/{group}/{version}/{kind}",
+			"name":        "crd",
+			"description": "Skeleton YAML and allowed fields for a discovered apiVersion/kind (group \"core\" for no-group APIs).",
+			"mimeType":    "text/yaml",
+		},
+		liveResourceTemplate(),
+	}
+}
+
+// ReadResource resolves a datum://crd/... URI to its contents: the skeleton
+// YAML for the kind followed by its allowed field paths as a comment block.
+func (s *Service) ReadResource(uri string) (ResourceContents, error) {
+	apiVersion, kind, ok := parseResourceURI(uri)
+	if !ok {
+		return ResourceContents{}, fmt.Errorf("unrecognized resource URI %q", uri)
+	}
+	if !s.Disc.Has(apiVersion, kind) {
+		return ResourceContents{}, fmt.Errorf("%s/%s is not known to the control plane", apiVersion, kind)
+	}
+	skel, err := s.Disc.Skeleton(apiVersion, kind)
+	if err != nil {
+		return ResourceContents{}, err
+	}
+	supported, err := s.ListSupported(ListSupReq{APIVersion: apiVersion, Kind: kind})
+	if err != nil {
+		return ResourceContents{}, err
+	}
+	var b strings.Builder
+	b.WriteString(skel)
+	b.WriteString("\n# Allowed fields:\n")
+	for _, p := range supported.Paths {
+		fmt.Fprintf(&b, "#   %s\n", p)
+	}
+	return ResourceContents{URI: uri, MimeType: "text/yaml", Text: b.String()}, nil
+}
+
+// ListPrompts returns the fixed set of prompts this server registers.
+// Arguments are filled in from discovery at prompts/get time.
+func (s *Service) ListPrompts() []Prompt {
+	return []Prompt{
+		{
+			Name:        "create-workload",
+			Description: "Draft a new manifest for a discovered apiVersion/kind, pre-filled from its skeleton.",
+			Arguments: []PromptArgument{
+				{Name: "apiVersion", Description: "apiVersion of the kind to create", Required: true},
+				{Name: "kind", Description: "Kind to create", Required: true},
+			},
+		},
+		{
+			Name:        "fix-validation-errors",
+			Description: "Given a manifest and the datum_validate_crd details for it, propose a corrected manifest.",
+			Arguments: []PromptArgument{
+				{Name: "yaml", Description: "The manifest that failed validation", Required: true},
+				{Name: "details", Description: "The ValResp.Details returned by datum_validate_crd", Required: true},
+			},
+		},
+		{
+			Name:        "explain-kind",
+			Description: "Explain what a discovered apiVersion/kind is for and which fields are supported.",
+			Arguments: []PromptArgument{
+				{Name: "apiVersion", Description: "apiVersion of the kind to explain", Required: true},
+				{Name: "kind", Description: "Kind to explain", Required: true},
+			},
+		},
+	}
+}
+
+// GetPrompt renders name's message list using args, pulling skeleton/allowed
+// field data from discovery where relevant.
+func (s *Service) GetPrompt(name string, args map[string]string) ([]PromptMessage, error) {
+	text := func(role, content string) PromptMessage {
+		var m PromptMessage
+		m.Role = role
+		m.Content.Type = "text"
+		m.Content.Text = content
+		return m
+	}
+
+	switch name {
+	case "create-workload":
+		apiVersion, kind := args["apiVersion"], args["kind"]
+		if !s.Disc.Has(apiVersion, kind) {
+			return nil, fmt.Errorf("%s/%s is not known to the control plane", apiVersion, kind)
+		}
+		skel, err := s.Disc.Skeleton(apiVersion, kind)
+		if err != nil {
+			return nil, err
+		}
+		return []PromptMessage{text("user", fmt.Sprintf(
+			"Draft a %s/%s manifest starting from this skeleton, filling in the fields I ask for:\n\n%s",
+			apiVersion, kind, skel,
+		))}, nil
+
+	case "fix-validation-errors":
+		return []PromptMessage{text("user", fmt.Sprintf(
+			"This manifest failed datum_validate_crd:\n\n%s\n\nValidation details:\n%s\n\nPropose a corrected manifest.",
+			args["yaml"], args["details"],
+		))}, nil
+
+	case "explain-kind":
+		apiVersion, kind := args["apiVersion"], args["kind"]
+		if !s.Disc.Has(apiVersion, kind) {
+			return nil, fmt.Errorf("%s/%s is not known to the control plane", apiVersion, kind)
+		}
+		supported, err := s.ListSupported(ListSupReq{APIVersion: apiVersion, Kind: kind})
+		if err != nil {
+			return nil, err
+		}
+		return []PromptMessage{text("user", fmt.Sprintf(
+			"Explain what %s/%s is for and how its supported fields (%s) are used.",
+			apiVersion, kind, strings.Join(supported.Paths, ", "),
+		))}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown prompt %q", name)
+	}
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SchemaReq struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Format selects the output shape: "openapi" (default) returns the raw
+	// OpenAPI v3 schema fragment from discovery as-is; "draft-07" converts
+	// it to JSON Schema draft-07 (nullable -> type:[T,"null"], plus a
+	// "$schema" field). x-kubernetes-* extensions (preserve-unknown-fields,
+	// int-or-string, list-map-keys, ...) are preserved either way, since
+	// downstream SSA-aware tooling needs them.
+	Format string `json:"format,omitempty"`
+}
+type SchemaResp struct {
+	Schema any `json:"schema"`
+}
+
+// GetSchema returns the discovery cache's raw OpenAPI v3 schema fragment for
+// apiVersion/kind, optionally converted to draft-07 JSON Schema, so
+// IDE/LLM clients can feed it straight into their own validators or
+// code-generators instead of our path-flattened AllowedSpec.
+func (s *Service) GetSchema(r SchemaReq) (SchemaResp, error) {
+	if !s.Disc.Has(r.APIVersion, r.Kind) {
+		return SchemaResp{}, fmt.Errorf("Unknown apiVersion/kind")
+	}
+	raw := s.Disc.GetSchema(r.APIVersion, r.Kind)
+
+	switch strings.ToLower(strings.TrimSpace(r.Format)) {
+	case "", "openapi", "openapi-v3":
+		return SchemaResp{Schema: raw}, nil
+	case "draft-07", "jsonschema", "json-schema":
+		converted, _ := toJSONSchemaDraft07(raw).(map[string]any)
+		converted["$schema"] = "http://json-schema.org/draft-07/schema#"
+		return SchemaResp{Schema: converted}, nil
+	default:
+		return SchemaResp{}, fmt.Errorf("unsupported format %q (use openapi|draft-07)", r.Format)
+	}
+}
+
+// toJSONSchemaDraft07 recursively rewrites OpenAPI-isms that draft-07 JSON
+// Schema doesn't share: "nullable: true" becomes a "null" member of "type"
+// (draft-07 already allows "type" to be an array). Everything else,
+// including x-kubernetes-* extensions, passes through unchanged.
+func toJSONSchemaDraft07(node any) any {
+	switch t := node.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, v := range t {
+			out[k] = toJSONSchemaDraft07(v)
+		}
+		if nullable, _ := out["nullable"].(bool); nullable {
+			delete(out, "nullable")
+			switch ty := out["type"].(type) {
+			case string:
+				out["type"] = []any{ty, "null"}
+			case []any:
+				hasNull := false
+				for _, e := range ty {
+					if s, _ := e.(string); s == "null" {
+						hasNull = true
+						break
+					}
+				}
+				if !hasNull {
+					out["type"] = append(ty, "null")
+				}
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, v := range t {
+			out[i] = toJSONSchemaDraft07(v)
+		}
+		return out
+	default:
+		return t
+	}
+}
@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+)
+
+// ServeOptions configures which transports Serve starts. STDIO always runs
+// (it's the transport every MCP client launches this binary with); the
+// others are opt-in.
+type ServeOptions struct {
+	// StreamableAddr, when set, also serves the Streamable-HTTP/SSE MCP
+	// transport (see ServeMCP) on this address for remote clients.
+	StreamableAddr string
+	// LegacyREST, when true, also serves the bespoke pre-MCP /datum/* REST
+	// endpoints (see ServeHTTP) on LegacyRESTPort, for callers that haven't
+	// migrated to a real MCP client yet.
+	LegacyREST     bool
+	LegacyRESTPort int
+}
+
+// Serve is the single entry point for every datum-mcp transport: it starts
+// whichever of the Streamable-HTTP and legacy-REST listeners opts asks for
+// in the background, then blocks running the STDIO JSON-RPC loop (the
+// transport every MCP client launches this binary with) until stdin closes.
+func Serve(s *Service, opts ServeOptions) {
+	if opts.StreamableAddr != "" {
+		go func() {
+			if err := ServeMCP(s, opts.StreamableAddr); err != nil {
+				fmt.Fprintf(os.Stderr, "[datum-mcp] Streamable-HTTP transport error: %v\n", err)
+			}
+		}()
+	}
+	if opts.LegacyREST {
+		go func() {
+			if err := ServeHTTP(s, opts.LegacyRESTPort); err != nil {
+				fmt.Fprintf(os.Stderr, "[datum-mcp] legacy REST transport error: %v\n", err)
+			}
+		}()
+	}
+	s.RunSTDIO(0)
+}
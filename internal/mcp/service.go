@@ -2,33 +2,60 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/datum-cloud/datum-mcp/internal/api"
+	"github.com/datum-cloud/datum-mcp/internal/auth"
 	"github.com/datum-cloud/datum-mcp/internal/discovery"
+	"github.com/datum-cloud/datum-mcp/internal/project"
+	"github.com/datum-cloud/datum-mcp/internal/watcher"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
 	"gopkg.in/yaml.v3"
 )
 
+// defaultFieldManager is recorded in managedFields when a caller doesn't
+// supply one for datum_validate_crd/datum_apply_crd.
+const defaultFieldManager = "datum-mcp"
+
 // Service implements the MCP tools using a Discovery cache.
 type Service struct {
 	Disc *discovery.Cache
 	// Optional allow-lists for metadata keys to preserve.
 	AllowedMetaAnnotations map[string]struct{}
 	AllowedMetaLabels      map[string]struct{}
+
+	// sessions tracks Streamable-HTTP sessions minted by handleMCPPost's
+	// "initialize" handling; see newSession/session in sse.go.
+	sessMu   sync.Mutex
+	sessions map[string]*mcpSession
+
+	// Watch shares informer-backed live resource views (see live_resources.go)
+	// across every datum://project/... resources/read and resources/subscribe
+	// call, the same way Disc shares the discovery cache.
+	Watch *watcher.Manager
 }
 
 func NewService(d *discovery.Cache) *Service {
 	return &Service{
-		Disc: d,
+		Disc:                   d,
 		AllowedMetaAnnotations: map[string]struct{}{},
 		AllowedMetaLabels:      map[string]struct{}{},
+		Watch:                  watcher.NewManager(),
 	}
 }
 
 type ListCRDsResp struct {
-	CRDs [][2]string `json:"crds"`
+	CRDs []discovery.CRDInfo `json:"crds"`
 }
 
 type SkeletonReq struct {
@@ -41,10 +68,32 @@ type SkeletonResp struct {
 
 type PruneReq struct {
 	YAML string `json:"yaml"`
+	// Project selects the control-plane project to fetch the live object's
+	// managedFields from; falls back to the active project when empty.
+	// Only consulted when FieldManager is set.
+	Project string `json:"project,omitempty"`
+	// FieldManager, when set, makes pruning SSA-ownership-aware: a field
+	// that isn't in our allow-list is only stripped if it's unknown to the
+	// live object or owned by a different manager (see RemovedField.Reason);
+	// fields already owned by FieldManager itself are left alone.
+	FieldManager string `json:"fieldManager,omitempty"`
 }
 type PruneResp struct {
-	YAML    string   `json:"yaml"`
-	Removed []string `json:"removed"`
+	YAML    string         `json:"yaml"`
+	Removed []RemovedField `json:"removed"`
+}
+
+// RemovedField describes one field datum_prune_crd stripped.
+type RemovedField struct {
+	Path string `json:"path"`
+	// Reason is one of "unknown-field" (not in our schema and not owned by
+	// any manager), "conflict" (owned by a different SSA field manager), or
+	// "not-allowed-label" (a metadata annotation/label outside the
+	// configured allow-list).
+	Reason string `json:"reason"`
+	// Owner is the field manager that owns this field on the live object,
+	// set only when Reason is "conflict".
+	Owner string `json:"owner,omitempty"`
 }
 
 type ListSupReq struct {
@@ -57,10 +106,36 @@ type ListSupResp struct {
 
 type ValReq struct {
 	YAML string `json:"yaml"`
+	// Project selects the control-plane project to dry-run against; falls
+	// back to the active project (see the `projects` tool) when empty.
+	Project string `json:"project,omitempty"`
+	// FieldManager is recorded in managedFields for the dry-run request;
+	// defaults to defaultFieldManager when empty.
+	FieldManager string `json:"fieldManager,omitempty"`
 }
 type ValResp struct {
-	Valid   bool   `json:"valid"`
-	Details string `json:"details"`
+	Valid bool `json:"valid"`
+	// Details is a human-readable message on the local/schema-only paths,
+	// or the structured metav1.Status (causes, field paths) the API server
+	// returned for the dry-run apply.
+	Details any `json:"details"`
+}
+
+type ApplyReq struct {
+	YAML string `json:"yaml"`
+	// Project selects the control-plane project to apply against; falls
+	// back to the active project (see the `projects` tool) when empty.
+	Project string `json:"project,omitempty"`
+	// FieldManager is recorded in managedFields; defaults to
+	// defaultFieldManager when empty.
+	FieldManager string `json:"fieldManager,omitempty"`
+}
+type ApplyResp struct {
+	Applied bool `json:"applied"`
+	Object  any  `json:"object,omitempty"`
+	// Details carries the structured metav1.Status returned by the API
+	// server when Applied is false.
+	Details any `json:"details,omitempty"`
 }
 
 func (s *Service) ListCRDs() ListCRDsResp {
@@ -103,77 +178,296 @@ func (s *Service) ListSupported(r ListSupReq) (ListSupResp, error) {
 	return ListSupResp{Paths: paths}, nil
 }
 
-func (s *Service) Prune(r PruneReq) (PruneResp, error) {
-	cleaned, removedSpec, removedMetaOrTop, err := s.pruneImpl(r.YAML)
+func (s *Service) Prune(ctx context.Context, r PruneReq) (PruneResp, error) {
+	cleaned, removed, err := s.pruneImpl(ctx, r.YAML, r.Project, r.FieldManager)
 	if err != nil {
 		return PruneResp{}, err
 	}
-	removed := append(removedSpec, removedMetaOrTop...)
 	if len(removed) > 0 {
 		return PruneResp{}, &UnsupportedRemoved{Removed: removed}
 	}
-	return PruneResp{YAML: cleaned, Removed: []string{}}, nil
+	return PruneResp{YAML: cleaned, Removed: []RemovedField{}}, nil
 }
 
-type UnsupportedRemoved struct{ Removed []string }
+type UnsupportedRemoved struct{ Removed []RemovedField }
 
 func (e *UnsupportedRemoved) Error() string {
 	lines := make([]string, len(e.Removed))
 	for i, r := range e.Removed {
-		lines[i] = "- " + r
+		if r.Owner != "" {
+			lines[i] = fmt.Sprintf("- %s (%s, owner=%s)", r.Path, r.Reason, r.Owner)
+		} else {
+			lines[i] = fmt.Sprintf("- %s (%s)", r.Path, r.Reason)
+		}
 	}
 	return "Unsupported fields stripped:\n" + strings.Join(lines, "\n")
 }
 
-func (s *Service) Validate(r ValReq) ValResp {
-	// Parse YAML first
-	var tmp any
-	if err := yaml.Unmarshal([]byte(r.YAML), &tmp); err != nil {
-		return ValResp{Valid: false, Details: fmt.Sprintf("Invalid YAML: %v", err)}
+// SchemaInvalid reports the structural/value violations discovery.Validate
+// found (wrong type, missing required field, enum/pattern/range mismatch,
+// ...), so a caller gets a concrete reason instead of a generic rejection
+// once the manifest reaches the API server.
+type SchemaInvalid struct{ Errors []discovery.ValidationError }
+
+func (e *SchemaInvalid) Error() string {
+	lines := make([]string, len(e.Errors))
+	for i, ve := range e.Errors {
+		lines[i] = "- " + ve.Error()
+	}
+	return "Schema validation failed:\n" + strings.Join(lines, "\n")
+}
+
+// validateDocs runs discovery.Cache.Validate against every decoded document
+// and returns every violation found, prefixing each path with "doc[i]" when
+// there's more than one document (mirroring how pruneImpl prefixes
+// RemovedField.Path for multi-document manifests).
+func (s *Service) validateDocs(docs []map[string]any) []discovery.ValidationError {
+	multi := len(docs) > 1
+	var errs []discovery.ValidationError
+	for i, d := range docs {
+		apiVersion, _ := d["apiVersion"].(string)
+		kind, _ := d["kind"].(string)
+		for _, ve := range s.Disc.Validate(apiVersion, kind, d) {
+			if multi {
+				ve.Path = fmt.Sprintf("doc[%d]%s", i, ve.Path)
+			}
+			errs = append(errs, ve)
+		}
+	}
+	return errs
+}
+
+func (s *Service) Validate(ctx context.Context, r ValReq) ValResp {
+	docs, err := decodeDocuments(r.YAML)
+	if err != nil {
+		return ValResp{Valid: false, Details: err.Error()}
+	}
+	if len(docs) == 0 {
+		return ValResp{Valid: false, Details: "no YAML/JSON documents found"}
 	}
 	// Detect what prune would remove (but do not remove it)
-	_, badSpec, badMetaOrTop, err := s.pruneImpl(r.YAML)
+	_, removed, err := s.pruneImpl(ctx, r.YAML, r.Project, r.FieldManager)
 	if err != nil {
 		// Unknown api/kind or parse error surfaced during prune
 		return ValResp{Valid: false, Details: err.Error()}
 	}
-	removed := append(badSpec, badMetaOrTop...)
 	if len(removed) > 0 {
+		paths := make([]string, len(removed))
+		for i, f := range removed {
+			paths[i] = f.Path
+		}
 		return ValResp{
 			Valid:   false,
-			Details: "Unsupported fields (local schema): " + strings.Join(removed, ", "),
+			Details: "Unsupported fields (local schema): " + strings.Join(paths, ", "),
+		}
+	}
+	if schemaErrs := s.validateDocs(docs); len(schemaErrs) > 0 {
+		return ValResp{Valid: false, Details: (&SchemaInvalid{Errors: schemaErrs}).Error()}
+	}
+	if len(docs) > 1 {
+		// A real dry-run apply targets exactly one object; a multi-document
+		// manifest (e.g. Kustomize/Helm output, or a List) only gets the
+		// local schema check.
+		return ValResp{
+			Valid:   true,
+			Details: fmt.Sprintf("Local schema check passed for %d documents (no cluster dry-run: multi-document manifest).", len(docs)),
+		}
+	}
+	// Local schema check passed; ask the API server for a real dry-run apply
+	// (?dryRun=All&fieldValidation=Strict), mirroring kubectl apply.
+	_, details, err := s.applyImpl(ctx, r.YAML, r.Project, r.FieldManager, true)
+	if err != nil {
+		if details != nil {
+			return ValResp{Valid: false, Details: details}
+		}
+		return ValResp{Valid: false, Details: err.Error()}
+	}
+	return ValResp{Valid: true, Details: "Server dry-run (Strict field validation) passed."}
+}
+
+// Apply performs a real (non-dry-run) create-or-update against the API
+// server, pruning unsupported fields first the same way datum_prune_crd
+// would. It targets exactly one object; use datum_prune_crd/datum_validate_crd
+// for multi-document manifests.
+func (s *Service) Apply(ctx context.Context, r ApplyReq) (ApplyResp, error) {
+	if docs, err := decodeDocuments(r.YAML); err == nil && len(docs) > 1 {
+		return ApplyResp{}, fmt.Errorf("datum_apply_crd applies a single object; got %d documents", len(docs))
+	}
+	cleaned, removed, err := s.pruneImpl(ctx, r.YAML, r.Project, r.FieldManager)
+	if err != nil {
+		return ApplyResp{}, err
+	}
+	if len(removed) > 0 {
+		return ApplyResp{}, &UnsupportedRemoved{Removed: removed}
+	}
+	if cleanedDocs, derr := decodeDocuments(cleaned); derr == nil {
+		if schemaErrs := s.validateDocs(cleanedDocs); len(schemaErrs) > 0 {
+			return ApplyResp{}, &SchemaInvalid{Errors: schemaErrs}
+		}
+	}
+	obj, details, err := s.applyImpl(ctx, cleaned, r.Project, r.FieldManager, false)
+	if err != nil {
+		if details != nil {
+			return ApplyResp{Applied: false, Details: details}, err
+		}
+		return ApplyResp{}, err
+	}
+	return ApplyResp{Applied: true, Object: obj}, nil
+}
+
+// applyImpl resolves a project-scoped control-plane client and runs
+// api.DryRunApply for doc, returning the API server's structured
+// metav1.Status as details whenever the failure is a *apierrors.StatusError.
+func (s *Service) applyImpl(ctx context.Context, doc, projectOverride, fieldManager string, dryRun bool) (*unstructured.Unstructured, any, error) {
+	var m map[string]any
+	if err := yaml.Unmarshal([]byte(doc), &m); err != nil {
+		return nil, nil, fmt.Errorf("Invalid YAML: %w", err)
+	}
+	apiVersion, _ := m["apiVersion"].(string)
+	kind, _ := m["kind"].(string)
+	if !s.Disc.Has(apiVersion, kind) {
+		return nil, nil, fmt.Errorf("%s/%s is not known to the control plane", apiVersion, kind)
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid apiVersion %q: %w", apiVersion, err)
+	}
+	meta, _ := m["metadata"].(map[string]any)
+	name, _ := meta["name"].(string)
+	if name == "" {
+		return nil, nil, fmt.Errorf("metadata.name is required")
+	}
+	namespace, _ := meta["namespace"].(string)
+
+	cli, err := s.controlPlaneClient(ctx, projectOverride)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fm := fieldManager
+	if fm == "" {
+		fm = defaultFieldManager
+	}
+	obj, err := api.DryRunApply(ctx, cli, gv.Group, kind, namespace, name, m, fm, dryRun)
+	if err != nil {
+		if status, ok := api.StatusErrorDetails(err); ok {
+			return nil, status, err
 		}
+		return nil, nil, err
+	}
+	return obj, nil, nil
+}
+
+// controlPlaneClient resolves the project to operate against (projectOverride,
+// falling back to the active project) and builds an authenticated client for
+// it, the same way the RoutedInput-backed tools in internal/server do.
+func (s *Service) controlPlaneClient(ctx context.Context, projectOverride string) (ctrlclient.Client, error) {
+	if _, err := auth.EnsureAuth(ctx); err != nil {
+		return nil, err
 	}
-	return ValResp{Valid: true, Details: "Local schema check passed (no cluster dry-run)."}
+	p := projectOverride
+	if p == "" {
+		p, _ = project.GetActive()
+	}
+	if p == "" {
+		return nil, fmt.Errorf("no active project set; pass 'project' or set one via the projects tool")
+	}
+	return api.NewProjectControlPlaneClient(ctx, p, nil)
 }
 
+// RefreshDiscovery reloads the CRD/OpenAPI schema cache and flushes the HTTP
+// response cache for the active project's control-plane host, so a single
+// call undoes both layers of staleness an agent might hit mid-session.
+// Flushing is best-effort: a project-resolution failure (e.g. none set yet)
+// doesn't fail the refresh itself.
 func (s *Service) RefreshDiscovery() (ok bool, count int, err error) {
 	if err := s.Disc.Refresh(context.Background()); err != nil {
 		return false, 0, err
 	}
+	p, _ := project.GetActive()
+	if host, herr := api.CurrentAPIHostname(context.Background(), p); herr == nil && host != "" {
+		api.FlushHTTPCacheForHost(host)
+	}
 	return true, s.Disc.FullCount(), nil
 }
 
+// CacheStats returns the HTTP response cache's hit/miss/eviction counters,
+// keyed by control-plane host, for /datum/cache_stats.
+func (s *Service) CacheStats() map[string]api.CacheStats {
+	return api.CacheStatsByHost()
+}
+
 // ------------------- internals: prune implementation -------------------
 
-func (s *Service) pruneImpl(doc string) (cleaned string, removedSpec, removedMetaOrTop []string, err error) {
-	var data any
-	if err := yaml.Unmarshal([]byte(doc), &data); err != nil {
-		return "", nil, nil, fmt.Errorf("Invalid YAML: %w", err)
+// pruneImpl prunes every document in doc (YAML or JSON; `---`-separated,
+// and/or a single `kind: *List` with items, recursed into its members) and
+// re-joins the cleaned documents as a `---`-separated stream in their
+// original order. removed paths are prefixed with "doc[N]." whenever doc
+// contains more than one document, so callers can tell which manifest a
+// stripped field came from. fieldManager, when set, makes the prune SSA-
+// ownership-aware (see pruneOne); projectOverride selects which project's
+// live object to consult.
+func (s *Service) pruneImpl(ctx context.Context, doc, projectOverride, fieldManager string) (cleaned string, removed []RemovedField, err error) {
+	docs, err := decodeDocuments(doc)
+	if err != nil {
+		return "", nil, err
 	}
-	m, ok := data.(map[string]any)
-	if !ok {
-		m = map[string]any{}
+	if len(docs) == 0 {
+		return "", nil, fmt.Errorf("no YAML/JSON documents found")
+	}
+	multi := len(docs) > 1
+
+	cleanedDocs := make([]string, 0, len(docs))
+	for i, d := range docs {
+		out, rFields, perr := s.pruneOne(ctx, d, projectOverride, fieldManager)
+		if perr != nil {
+			return "", nil, perr
+		}
+		if multi {
+			for j := range rFields {
+				rFields[j].Path = fmt.Sprintf("doc[%d].%s", i, rFields[j].Path)
+			}
+		}
+		removed = append(removed, rFields...)
+		b, merr := yaml.Marshal(out)
+		if merr != nil {
+			return "", nil, merr
+		}
+		cleanedDocs = append(cleanedDocs, string(b))
 	}
+	return strings.Join(cleanedDocs, "---\n"), removed, nil
+}
 
-	api, _ := m["apiVersion"].(string)
+// pruneOne applies the allow-list prune to a single already-decoded
+// document. When fieldManager is set, it fetches the live object's
+// metadata.managedFields and only strips an unsupported field if it's
+// unknown to the live object or owned by a different manager; a field
+// already owned by fieldManager itself is left in place even though it
+// isn't in our static allow-list, so co-managed manifests don't lose their
+// own prior writes.
+func (s *Service) pruneOne(ctx context.Context, m map[string]any, projectOverride, fieldManager string) (out map[string]any, removed []RemovedField, err error) {
+	apiVersion, _ := m["apiVersion"].(string)
 	kind, _ := m["kind"].(string)
-	if !s.Disc.Has(api, kind) {
-		return "", nil, nil, fmt.Errorf("%s/%s is not known to the control plane", api, kind)
+	if !s.Disc.Has(apiVersion, kind) {
+		return nil, nil, fmt.Errorf("%s/%s is not known to the control plane", apiVersion, kind)
+	}
+
+	var owners map[string]string
+	if fieldManager != "" {
+		owners = s.liveFieldOwners(ctx, apiVersion, kind, m, projectOverride)
+	}
+	classify := func(path string) RemovedField {
+		if owner, ok := owners[path]; ok && owner != fieldManager {
+			return RemovedField{Path: path, Reason: "conflict", Owner: owner}
+		}
+		return RemovedField{Path: path, Reason: "unknown-field"}
+	}
+	ownedByUs := func(path string) bool {
+		return fieldManager != "" && owners[path] == fieldManager
 	}
 
 	// ----- prune spec.* against allow-list ------------------------------
-	if a := s.Disc.AllowedSpec(api, kind); a != nil {
+	if a := s.Disc.AllowedSpec(apiVersion, kind); a != nil {
 		var walk func(node any, dotted string)
 		walk = func(node any, dotted string) {
 			switch x := node.(type) {
@@ -186,7 +480,10 @@ func (s *Service) pruneImpl(doc string) (cleaned string, removedSpec, removedMet
 					here += k
 					clean := discovery.StripIndices(here)
 					if strings.HasPrefix(clean, "spec.") && !discovery.IsAllowed(a, clean) {
-						removedSpec = append(removedSpec, clean)
+						if ownedByUs(clean) {
+							continue
+						}
+						removed = append(removed, classify(clean))
 						delete(x, k)
 						continue
 					}
@@ -206,7 +503,7 @@ func (s *Service) pruneImpl(doc string) (cleaned string, removedSpec, removedMet
 		if ann, ok := meta["annotations"].(map[string]any); ok {
 			for k := range ann {
 				if _, keep := s.AllowedMetaAnnotations[k]; !keep {
-					removedMetaOrTop = append(removedMetaOrTop, "metadata.annotations."+k)
+					removed = append(removed, RemovedField{Path: "metadata.annotations." + k, Reason: "not-allowed-label"})
 					delete(ann, k)
 				}
 			}
@@ -217,7 +514,7 @@ func (s *Service) pruneImpl(doc string) (cleaned string, removedSpec, removedMet
 		if lab, ok := meta["labels"].(map[string]any); ok {
 			for k := range lab {
 				if _, keep := s.AllowedMetaLabels[k]; !keep {
-					removedMetaOrTop = append(removedMetaOrTop, "metadata.labels."+k)
+					removed = append(removed, RemovedField{Path: "metadata.labels." + k, Reason: "not-allowed-label"})
 					delete(lab, k)
 				}
 			}
@@ -231,7 +528,7 @@ func (s *Service) pruneImpl(doc string) (cleaned string, removedSpec, removedMet
 	}
 
 	// ----- drop stray top-level keys using discovered props -------------
-	allowedTop := s.Disc.TopAllowed(api, kind)
+	allowedTop := s.Disc.TopAllowed(apiVersion, kind)
 	always := map[string]struct{}{"apiVersion": {}, "kind": {}, "metadata": {}}
 	for k := range m {
 		if _, ok := allowedTop[k]; ok {
@@ -240,19 +537,144 @@ func (s *Service) pruneImpl(doc string) (cleaned string, removedSpec, removedMet
 		if _, ok := always[k]; ok {
 			continue
 		}
-		removedMetaOrTop = append(removedMetaOrTop, k)
+		if ownedByUs(k) {
+			continue
+		}
+		removed = append(removed, classify(k))
 		delete(m, k)
 	}
 
-	out, err := yaml.Marshal(m)
+	return m, removed, nil
+}
+
+// liveFieldOwners fetches the live object named in m (if any) and returns
+// its field ownership map (see fieldOwners); it returns nil on any error or
+// not-found, so an ownership-aware prune degrades gracefully to the plain
+// "unknown-field" behavior instead of failing outright.
+func (s *Service) liveFieldOwners(ctx context.Context, apiVersion, kind string, m map[string]any, projectOverride string) map[string]string {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil
+	}
+	meta, _ := m["metadata"].(map[string]any)
+	name, _ := meta["name"].(string)
+	if name == "" {
+		return nil
+	}
+	namespace, _ := meta["namespace"].(string)
+
+	cli, err := s.controlPlaneClient(ctx, projectOverride)
 	if err != nil {
-		return "", nil, nil, err
+		return nil
+	}
+	live, err := api.FetchObject(ctx, cli, gv.Group, kind, namespace, name)
+	if err != nil || live == nil {
+		return nil
+	}
+	return fieldOwners(live)
+}
+
+// fieldOwners decodes obj's metadata.managedFields into a map from
+// StripIndices-normalized dotted path (e.g. "spec.foo.bar") to the manager
+// that owns it.
+func fieldOwners(obj *unstructured.Unstructured) map[string]string {
+	out := map[string]string{}
+	for _, mf := range obj.GetManagedFields() {
+		if mf.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(mf.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		walkFieldsV1(fields, "", mf.Manager, out)
+	}
+	return out
+}
+
+// walkFieldsV1 recurses a decoded FieldsV1 document, attributing each
+// "f:<name>" path component to manager. Non-"f:" entries ("k:{...}" list-map
+// keys, "v:..." set values) don't contribute a stable path component, so
+// nested f: entries under them are attributed at the same prefix.
+func walkFieldsV1(node map[string]any, prefix, manager string, out map[string]string) {
+	for k, v := range node {
+		switch {
+		case k == ".":
+			if prefix != "" {
+				out[prefix] = manager
+			}
+		case strings.HasPrefix(k, "f:"):
+			path := strings.TrimPrefix(k, "f:")
+			if prefix != "" {
+				path = prefix + "." + path
+			}
+			out[path] = manager
+			if child, ok := v.(map[string]any); ok {
+				walkFieldsV1(child, path, manager, out)
+			}
+		default:
+			if child, ok := v.(map[string]any); ok {
+				walkFieldsV1(child, prefix, manager, out)
+			}
+		}
+	}
+}
+
+// decodeDocuments reads every YAML (or JSON, a valid YAML subset) document
+// out of raw, expanding any `kind: *List` document (or top-level JSON
+// array of manifests) into its member documents so multi-doc Kustomize/Helm
+// output and `kubectl get ... -o json` List payloads behave the same as a
+// `---`-separated manifest.
+func decodeDocuments(raw string) ([]map[string]any, error) {
+	dec := yaml.NewDecoder(strings.NewReader(raw))
+	var out []map[string]any
+	for {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("Invalid YAML: %w", err)
+		}
+		if err := flattenDocs(v, &out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// flattenDocs appends v's constituent documents to out, recursing into
+// `kind: *List` items and plain sequences.
+func flattenDocs(v any, out *[]map[string]any) error {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		if kind, _ := t["kind"].(string); strings.HasSuffix(kind, "List") {
+			items, _ := t["items"].([]any)
+			for _, it := range items {
+				if err := flattenDocs(it, out); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		*out = append(*out, t)
+		return nil
+	case []any:
+		for _, it := range t {
+			if err := flattenDocs(it, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported document shape %T", v)
 	}
-	return string(out), removedSpec, removedMetaOrTop, nil
 }
 
 // Exported error inspector (useful for stdio/http layers to map codes)
-func IsUnsupportedRemoved(err error) (bool, []string) {
+func IsUnsupportedRemoved(err error) (bool, []RemovedField) {
 	var e *UnsupportedRemoved
 	if errors.As(err, &e) {
 		return true, e.Removed
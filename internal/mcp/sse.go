@@ -0,0 +1,304 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/datum-cloud/datum-mcp/internal/authutil"
+)
+
+// mcpSession tracks per-connection Streamable-HTTP state: a monotonically
+// increasing event ID for each SSE message sent on this session, and a
+// bounded replay buffer so a client reconnecting with Last-Event-ID after a
+// dropped connection doesn't lose notifications sent while it was away.
+type mcpSession struct {
+	id string
+
+	mu       sync.Mutex
+	nextID   uint64
+	buffered []sseEvent
+
+	// push carries server-initiated notifications (e.g.
+	// notifications/resources/updated from a resources/subscribe) to
+	// handleMCPGet's open SSE stream; a full push buffer means no GET stream
+	// is currently reading it, so notify drops rather than blocking, the same
+	// best-effort handling a reconnecting client recovers from via
+	// Last-Event-ID and the replay buffer above.
+	push chan sseEvent
+
+	subMu sync.Mutex
+	subs  map[string]func()
+}
+
+// sessionPushBufferSize bounds how many not-yet-delivered notifications a
+// session holds for its GET stream before notify starts dropping them.
+const sessionPushBufferSize = 32
+
+// notify implements resourceNotifier for the Streamable-HTTP transport: it
+// records method/params as a JSON-RPC notification and, if a GET stream is
+// currently open for this session, pushes it there immediately.
+func (sess *mcpSession) notify(method string, params map[string]any) {
+	ev := sess.record("message", jsonrpcResp{JSONRPC: "2.0", Method: method, Params: params})
+	select {
+	case sess.push <- ev:
+	default:
+	}
+}
+
+// addSubscription registers cancel under uri, replacing (and cancelling)
+// any prior subscription to the same uri.
+func (sess *mcpSession) addSubscription(uri string, cancel func()) {
+	sess.subMu.Lock()
+	defer sess.subMu.Unlock()
+	if sess.subs == nil {
+		sess.subs = map[string]func(){}
+	}
+	if old, ok := sess.subs[uri]; ok {
+		old()
+	}
+	sess.subs[uri] = cancel
+}
+
+// removeSubscription removes and returns uri's cancel func, if subscribed.
+func (sess *mcpSession) removeSubscription(uri string) (func(), bool) {
+	sess.subMu.Lock()
+	defer sess.subMu.Unlock()
+	cancel, ok := sess.subs[uri]
+	if ok {
+		delete(sess.subs, uri)
+	}
+	return cancel, ok
+}
+
+type sseEvent struct {
+	id      uint64
+	event   string
+	payload any
+}
+
+// sseReplayBufferSize bounds how many past events a session remembers for
+// Last-Event-ID replay; callers that reconnect after a longer gap just miss
+// the oldest ones rather than the server holding history forever.
+const sseReplayBufferSize = 64
+
+// record assigns the next event ID to payload and appends it to the replay
+// buffer, dropping the oldest entry once full.
+func (sess *mcpSession) record(event string, payload any) sseEvent {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.nextID++
+	ev := sseEvent{id: sess.nextID, event: event, payload: payload}
+	sess.buffered = append(sess.buffered, ev)
+	if len(sess.buffered) > sseReplayBufferSize {
+		sess.buffered = sess.buffered[1:]
+	}
+	return ev
+}
+
+// replayAfter returns every buffered event with id > lastEventID, in order.
+func (sess *mcpSession) replayAfter(lastEventID uint64) []sseEvent {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	out := make([]sseEvent, 0, len(sess.buffered))
+	for _, ev := range sess.buffered {
+		if ev.id > lastEventID {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+// newSession creates and registers a fresh session, minted when a client
+// sends "initialize"; its ID is returned to the client via the
+// Mcp-Session-Id response header and must be echoed on every later request.
+func (s *Service) newSession() (*mcpSession, error) {
+	id, err := generateSessionID()
+	if err != nil {
+		return nil, err
+	}
+	sess := &mcpSession{id: id, push: make(chan sseEvent, sessionPushBufferSize)}
+	s.sessMu.Lock()
+	if s.sessions == nil {
+		s.sessions = map[string]*mcpSession{}
+	}
+	s.sessions[id] = sess
+	s.sessMu.Unlock()
+	return sess, nil
+}
+
+// session looks up a previously-minted session by the Mcp-Session-Id header.
+func (s *Service) session(id string) (*mcpSession, bool) {
+	s.sessMu.Lock()
+	defer s.sessMu.Unlock()
+	sess, ok := s.sessions[id]
+	return sess, ok
+}
+
+func generateSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ServeMCP starts the Streamable-HTTP MCP transport (2025-06-18 spec) on
+// addr: POST /mcp accepts a JSON-RPC request and answers with either a
+// single JSON response or an SSE stream for streamingTools, and GET /mcp
+// opens a long-lived SSE stream for server-initiated notifications. POST
+// shares handleRPC with RunSTDIO, and both endpoints require a bearer token
+// matching the locally active account (see requireBearerAuth).
+func ServeMCP(s *Service, addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+		if err := requireBearerAuth(r.Context(), r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			s.handleMCPPost(w, r)
+		case http.MethodGet:
+			s.handleMCPGet(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	return http.ListenAndServe(addr, mux)
+}
+
+func (s *Service) handleMCPPost(w http.ResponseWriter, r *http.Request) {
+	var req jsonrpcReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	var sess *mcpSession
+	if req.Method == "initialize" {
+		var err error
+		sess, err = s.newSession()
+		if err != nil {
+			http.Error(w, "failed to start session", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Mcp-Session-Id", sess.id)
+	} else {
+		id := r.Header.Get("Mcp-Session-Id")
+		if id == "" {
+			http.Error(w, "Mcp-Session-Id header is required", http.StatusBadRequest)
+			return
+		}
+		var ok bool
+		sess, ok = s.session(id)
+		if !ok {
+			http.Error(w, "unknown Mcp-Session-Id", http.StatusNotFound)
+			return
+		}
+	}
+
+	resp, stream := s.handleRPC(withNotifier(r.Context(), sess), req)
+	if stream == nil {
+		writeJSON(w, resp)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		// Transport can't stream; fall back to blocking for the one result.
+		writeJSON(w, <-stream)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	writeSSEEvent(w, flusher, sess.record("message", <-stream))
+}
+
+func (s *Service) handleMCPGet(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get("Mcp-Session-Id")
+	if id == "" {
+		http.Error(w, "Mcp-Session-Id header is required", http.StatusBadRequest)
+		return
+	}
+	sess, ok := s.session(id)
+	if !ok {
+		http.Error(w, "unknown Mcp-Session-Id", http.StatusNotFound)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusNotImplemented)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if last := r.Header.Get("Last-Event-ID"); last != "" {
+		if lastID, err := strconv.ParseUint(last, 10, 64); err == nil {
+			for _, ev := range sess.replayAfter(lastID) {
+				writeSSEEvent(w, flusher, ev)
+			}
+		}
+	}
+	flusher.Flush()
+	// Beyond replaying anything missed above, forward every notification a
+	// resources/subscribe on this session produces (see mcpSession.notify)
+	// until the client disconnects, per the Streamable-HTTP spec's GET
+	// contract.
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-sess.push:
+			writeSSEEvent(w, flusher, ev)
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, ev sseEvent) {
+	b, err := json.Marshal(ev.payload)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.id, ev.event, b)
+	flusher.Flush()
+}
+
+// requireBearerAuth checks the request's "Authorization: Bearer <token>"
+// header against the access token minted for the locally active account,
+// the same token internal/api uses to talk to the control plane. This lets
+// a remote MCP client authenticate with the credentials `datum login`
+// already stored in the keyring, without the server managing its own
+// separate credential store.
+func requireBearerAuth(ctx context.Context, r *http.Request) error {
+	const prefix = "Bearer "
+	got := r.Header.Get("Authorization")
+	if !strings.HasPrefix(got, prefix) {
+		return fmt.Errorf("missing bearer token")
+	}
+	token := strings.TrimPrefix(got, prefix)
+
+	ts, err := authutil.GetTokenSource(ctx)
+	if err != nil {
+		return fmt.Errorf("no active login: %w", err)
+	}
+	want, err := ts.Token()
+	if err != nil {
+		return fmt.Errorf("failed to mint access token: %w", err)
+	}
+	if token != want.AccessToken {
+		return fmt.Errorf("invalid bearer token")
+	}
+	return nil
+}
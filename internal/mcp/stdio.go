@@ -2,10 +2,12 @@ package mcp
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 )
 
 type jsonrpcReq struct {
@@ -30,12 +32,51 @@ type jsonrpcError struct {
 }
 
 var ignored = map[string]bool{
-	"resources/list":            true,
-	"prompts/list":              true,
 	"notifications/cancelled":   true,
 	"notifications/initialized": true,
 }
 
+// streamingTools lists tools/call names whose work can take long enough to
+// warrant streaming the result over SSE rather than holding an HTTP request
+// open with nothing to show; handleRPC runs them on a channel instead of
+// returning synchronously.
+var streamingTools = map[string]bool{
+	"datum_refresh_discovery": true,
+	"datum_apply_crd":         true,
+}
+
+// stdioNotifier implements resourceNotifier for RunSTDIO's single
+// connection: notify writes a notification straight to stdout, and
+// subscriptions live in a package-level map since a STDIO process serves
+// exactly one client for its lifetime.
+type stdioNotifier struct{}
+
+var (
+	stdioSubsMu sync.Mutex
+	stdioSubs   = map[string]func(){}
+)
+
+func (stdioNotifier) notify(method string, params map[string]any) { notify(method, params) }
+
+func (stdioNotifier) addSubscription(uri string, cancel func()) {
+	stdioSubsMu.Lock()
+	defer stdioSubsMu.Unlock()
+	if old, ok := stdioSubs[uri]; ok {
+		old()
+	}
+	stdioSubs[uri] = cancel
+}
+
+func (stdioNotifier) removeSubscription(uri string) (func(), bool) {
+	stdioSubsMu.Lock()
+	defer stdioSubsMu.Unlock()
+	cancel, ok := stdioSubs[uri]
+	if ok {
+		delete(stdioSubs, uri)
+	}
+	return cancel, ok
+}
+
 func (s *Service) RunSTDIO(port int) {
 	fmt.Fprintf(os.Stderr, "[datum-mcp] STDIO mode ready\n")
 	// Optional HTTP for manual testing.
@@ -47,6 +88,8 @@ func (s *Service) RunSTDIO(port int) {
 		}()
 	}
 
+	ctx := withNotifier(context.Background(), stdioNotifier{})
+
 	sc := bufio.NewScanner(os.Stdin)
 	buf := make([]byte, 0, 1024*1024)
 	sc.Buffer(buf, 10*1024*1024)
@@ -60,124 +103,266 @@ func (s *Service) RunSTDIO(port int) {
 		if err := json.Unmarshal([]byte(line), &req); err != nil {
 			continue
 		}
-		switch req.Method {
-		case "initialize":
-			reply(jsonrpcResp{
-				JSONRPC: "2.0",
-				ID:      req.ID,
-				Result: map[string]any{
-					"protocolVersion": "2025-06-18",
-					"serverInfo": map[string]any{
-						"name":    "datum-mcp",
-						"version": "2.2.0",
-					},
-					"capabilities": map[string]any{},
-				},
-			})
+
+		resp, stream := s.handleRPC(ctx, req)
+		if stream != nil {
+			resp = <-stream
+		}
+		if resp.JSONRPC != "" || resp.Error != nil || resp.Result != nil {
+			reply(resp)
+		}
+		if req.Method == "initialize" {
 			notify("notifications/initialized", map[string]any{})
-			continue
+		}
+	}
+}
 
-		case "tools/list":
-			reply(jsonrpcResp{
-				JSONRPC: "2.0",
-				ID:      req.ID,
-				Result: map[string]any{
-					"tools": toolsList(),
+// handleRPC dispatches one JSON-RPC request and is the single code path
+// shared by the STDIO, plain-HTTP, and SSE transports. For ordinary
+// requests it returns a ready jsonrpcResp and a nil channel. For
+// streamingTools it returns a zero-value response and a channel that
+// yields exactly one response once the tool finishes, so the HTTP/SSE
+// transport can keep the connection open as an event stream in the
+// meantime instead of blocking with nothing to show.
+func (s *Service) handleRPC(ctx context.Context, req jsonrpcReq) (jsonrpcResp, <-chan jsonrpcResp) {
+	switch req.Method {
+	case "initialize":
+		return jsonrpcResp{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result: map[string]any{
+				"protocolVersion": "2025-06-18",
+				"serverInfo": map[string]any{
+					"name":    "datum-mcp",
+					"version": "2.2.0",
 				},
-			})
-			continue
+				"capabilities": map[string]any{
+					"resources": map[string]any{"subscribe": true},
+					"prompts":   map[string]any{},
+				},
+			},
+		}, nil
 
-		case "tools/call":
-			name, _ := req.Params["name"].(string)
-			args, _ := req.Params["arguments"].(map[string]any)
-			if name == "" {
-				replyErr(req.ID, -32602, "Missing tool name")
-				continue
-			}
-			switch name {
-			case "datum_list_crds":
-				res := s.ListCRDs()
-				replyToolOK(req.ID, res)
-
-			case "datum_skeleton_crd":
-				// Hidden from tools/list, still callable by name
-				var r SkeletonReq
-				if args != nil {
-					r.APIVersion, _ = args["apiVersion"].(string)
-					r.Kind, _ = args["kind"].(string)
-				}
-				resp, err := s.Skeleton(r)
-				if err != nil {
-					replyErr(req.ID, -32603, err.Error())
-					continue
-				}
-				replyToolOK(req.ID, resp)
-
-			case "datum_list_supported":
-				var r ListSupReq
-				if args != nil {
-					r.APIVersion, _ = args["apiVersion"].(string)
-					r.Kind, _ = args["kind"].(string)
-				}
-				resp, err := s.ListSupported(r)
-				if err != nil {
-					replyErr(req.ID, -32603, err.Error())
-					continue
-				}
-				replyToolOK(req.ID, resp)
-
-			case "datum_prune_crd":
-				var r PruneReq
-				if args != nil {
-					r.YAML, _ = args["yaml"].(string)
-				}
-				resp, err := s.Prune(r)
-				if err != nil {
-					if bad, _ := IsUnsupportedRemoved(err); bad {
-						replyErr(req.ID, -32603, err.Error())
-						continue
-					}
-					replyErr(req.ID, -32603, err.Error())
-					continue
-				}
-				replyToolOK(req.ID, resp)
-
-			case "datum_validate_crd":
-				var r ValReq
-				if args != nil {
-					r.YAML, _ = args["yaml"].(string)
-				}
-				resp := s.Validate(r)
-				replyToolOK(req.ID, resp)
-
-			case "datum_refresh_discovery":
-				ok, count, err := s.RefreshDiscovery()
-				if err != nil {
-					replyErr(req.ID, -32603, err.Error())
-					continue
-				}
-				replyToolOK(req.ID, map[string]any{"ok": ok, "count": count})
-
-			default:
-				replyErr(req.ID, -32601, fmt.Sprintf("Unknown tool %s", name))
-			}
-			continue
-		default:
-			if ignored[req.Method] {
-				if req.ID != nil {
-					root := strings.SplitN(req.Method, "/", 2)[0]
-					reply(jsonrpcResp{
-						JSONRPC: "2.0",
-						ID:      req.ID,
-						Result:  map[string]any{root: []any{}},
-					})
-				}
-				continue
-			}
-			if req.ID != nil {
-				replyErr(req.ID, -32601, "Unknown method "+req.Method)
+	case "tools/list":
+		return jsonrpcResp{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  map[string]any{"tools": toolsList()},
+		}, nil
+
+	case "resources/list":
+		return jsonrpcResp{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  map[string]any{"resources": s.ListResources()},
+		}, nil
+
+	case "resources/templates/list":
+		return jsonrpcResp{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  map[string]any{"resourceTemplates": s.ListResourceTemplates()},
+		}, nil
+
+	case "resources/read":
+		uri, _ := req.Params["uri"].(string)
+		readFn := s.ReadResource
+		if strings.HasPrefix(uri, liveResourcePrefix) {
+			readFn = func(uri string) (ResourceContents, error) { return s.ReadLiveResource(ctx, uri) }
+		}
+		res, err := readFn(uri)
+		if err != nil {
+			return errResp(req.ID, -32603, err.Error()), nil
+		}
+		return jsonrpcResp{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  map[string]any{"contents": []ResourceContents{res}},
+		}, nil
+
+	case "resources/subscribe":
+		uri, _ := req.Params["uri"].(string)
+		rn, ok := notifierFromContext(ctx)
+		if !ok {
+			return errResp(req.ID, -32603, "resources/subscribe requires a stateful transport"), nil
+		}
+		if err := s.subscribeResource(ctx, rn, uri); err != nil {
+			return errResp(req.ID, -32603, err.Error()), nil
+		}
+		return jsonrpcResp{JSONRPC: "2.0", ID: req.ID, Result: map[string]any{}}, nil
+
+	case "resources/unsubscribe":
+		uri, _ := req.Params["uri"].(string)
+		if rn, ok := notifierFromContext(ctx); ok {
+			s.unsubscribeResource(rn, uri)
+		}
+		return jsonrpcResp{JSONRPC: "2.0", ID: req.ID, Result: map[string]any{}}, nil
+
+	case "prompts/list":
+		return jsonrpcResp{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  map[string]any{"prompts": s.ListPrompts()},
+		}, nil
+
+	case "prompts/get":
+		name, _ := req.Params["name"].(string)
+		rawArgs, _ := req.Params["arguments"].(map[string]any)
+		args := make(map[string]string, len(rawArgs))
+		for k, v := range rawArgs {
+			args[k], _ = v.(string)
+		}
+		msgs, err := s.GetPrompt(name, args)
+		if err != nil {
+			return errResp(req.ID, -32603, err.Error()), nil
+		}
+		return jsonrpcResp{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  map[string]any{"messages": msgs},
+		}, nil
+
+	case "tools/call":
+		name, _ := req.Params["name"].(string)
+		args, _ := req.Params["arguments"].(map[string]any)
+		if name == "" {
+			return errResp(req.ID, -32602, "Missing tool name"), nil
+		}
+		if streamingTools[name] {
+			ch := make(chan jsonrpcResp, 1)
+			go func() {
+				ch <- s.callTool(ctx, req.ID, name, args)
+				close(ch)
+			}()
+			return jsonrpcResp{}, ch
+		}
+		return s.callTool(ctx, req.ID, name, args), nil
+
+	default:
+		if ignored[req.Method] {
+			if req.ID == nil {
+				return jsonrpcResp{}, nil
 			}
+			root := strings.SplitN(req.Method, "/", 2)[0]
+			return jsonrpcResp{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Result:  map[string]any{root: []any{}},
+			}, nil
 		}
+		if req.ID == nil {
+			return jsonrpcResp{}, nil
+		}
+		return errResp(req.ID, -32601, "Unknown method "+req.Method), nil
+	}
+}
+
+// callTool runs a single tools/call by name and builds its jsonrpcResp;
+// separated from handleRPC so streamingTools can be run on a goroutine.
+func (s *Service) callTool(ctx context.Context, id any, name string, args map[string]any) jsonrpcResp {
+	switch name {
+	case "datum_list_crds":
+		return toolOKResp(id, s.ListCRDs())
+
+	case "datum_skeleton_crd":
+		// Hidden from tools/list, still callable by name
+		var r SkeletonReq
+		if args != nil {
+			r.APIVersion, _ = args["apiVersion"].(string)
+			r.Kind, _ = args["kind"].(string)
+		}
+		resp, err := s.Skeleton(r)
+		if err != nil {
+			return errResp(id, -32603, err.Error())
+		}
+		return toolOKResp(id, resp)
+
+	case "datum_list_supported":
+		var r ListSupReq
+		if args != nil {
+			r.APIVersion, _ = args["apiVersion"].(string)
+			r.Kind, _ = args["kind"].(string)
+		}
+		resp, err := s.ListSupported(r)
+		if err != nil {
+			return errResp(id, -32603, err.Error())
+		}
+		return toolOKResp(id, resp)
+
+	case "datum_prune_crd":
+		var r PruneReq
+		if args != nil {
+			r.YAML, _ = args["yaml"].(string)
+			r.Project, _ = args["project"].(string)
+			r.FieldManager, _ = args["fieldManager"].(string)
+		}
+		resp, err := s.Prune(ctx, r)
+		if err != nil {
+			return errResp(id, -32603, err.Error())
+		}
+		return toolOKResp(id, resp)
+
+	case "datum_validate_crd":
+		var r ValReq
+		if args != nil {
+			r.YAML, _ = args["yaml"].(string)
+			r.Project, _ = args["project"].(string)
+			r.FieldManager, _ = args["fieldManager"].(string)
+		}
+		resp := s.Validate(ctx, r)
+		return toolOKResp(id, resp)
+
+	case "datum_apply_crd":
+		var r ApplyReq
+		if args != nil {
+			r.YAML, _ = args["yaml"].(string)
+			r.Project, _ = args["project"].(string)
+			r.FieldManager, _ = args["fieldManager"].(string)
+		}
+		resp, err := s.Apply(ctx, r)
+		if err != nil {
+			return errResp(id, -32603, err.Error())
+		}
+		return toolOKResp(id, resp)
+
+	case "datum_diff_crd":
+		var r DiffReq
+		if args != nil {
+			r.YAML, _ = args["yaml"].(string)
+			r.Project, _ = args["project"].(string)
+			r.FieldManager, _ = args["fieldManager"].(string)
+		}
+		resp, err := s.Diff(ctx, r)
+		if err != nil {
+			return errResp(id, -32603, err.Error())
+		}
+		return toolOKResp(id, resp)
+
+	case "datum_get_schema":
+		var r SchemaReq
+		if args != nil {
+			r.APIVersion, _ = args["apiVersion"].(string)
+			r.Kind, _ = args["kind"].(string)
+			r.Format, _ = args["format"].(string)
+		}
+		resp, err := s.GetSchema(r)
+		if err != nil {
+			return errResp(id, -32603, err.Error())
+		}
+		return toolOKResp(id, resp)
+
+	case "datum_refresh_discovery":
+		ok, count, err := s.RefreshDiscovery()
+		if err != nil {
+			return errResp(id, -32603, err.Error())
+		}
+		return toolOKResp(id, map[string]any{"ok": ok, "count": count})
+
+	case "datum_cache_stats":
+		return toolOKResp(id, s.CacheStats())
+
+	default:
+		return errResp(id, -32601, fmt.Sprintf("Unknown tool %s", name))
 	}
 }
 
@@ -191,20 +376,20 @@ func notify(method string, params map[string]any) {
 
 func reply(resp jsonrpcResp) { emit(resp) }
 
-func replyErr(id any, code int, msg string) {
-	reply(jsonrpcResp{
+func errResp(id any, code int, msg string) jsonrpcResp {
+	return jsonrpcResp{
 		JSONRPC: "2.0",
 		ID:      id,
 		Error:   &jsonrpcError{Code: code, Message: msg},
-	})
+	}
 }
 
-func replyToolOK(id any, payload any) {
+func toolOKResp(id any, payload any) jsonrpcResp {
 	b, err := json.MarshalIndent(payload, "", "  ")
 	if err != nil {
 		b, _ = json.Marshal(payload)
 	}
-	reply(jsonrpcResp{
+	return jsonrpcResp{
 		JSONRPC: "2.0",
 		ID:      id,
 		Result: map[string]any{
@@ -215,7 +400,7 @@ func replyToolOK(id any, payload any) {
 				},
 			},
 		},
-	})
+	}
 }
 
 func emit(resp jsonrpcResp) {
@@ -245,11 +430,13 @@ func toolsList() []map[string]any {
 		},
 		{
 			"name":        "datum_prune_crd",
-			"description": "Strip unsupported fields (422 if any were removed).",
+			"description": "Strip unsupported fields (422 if any were removed). When fieldManager is set, consults the live object's managedFields so fields already owned by fieldManager are kept instead of stripped.",
 			"inputSchema": map[string]any{
 				"type": "object",
 				"properties": map[string]any{
-					"yaml": map[string]any{"type": "string"},
+					"yaml":         map[string]any{"type": "string"},
+					"project":      map[string]any{"type": "string"},
+					"fieldManager": map[string]any{"type": "string"},
 				},
 				"required": []any{"yaml"},
 			},
@@ -260,15 +447,61 @@ func toolsList() []map[string]any {
 			"inputSchema": map[string]any{
 				"type": "object",
 				"properties": map[string]any{
-					"yaml": map[string]any{"type": "string"},
+					"yaml":         map[string]any{"type": "string"},
+					"project":      map[string]any{"type": "string"},
+					"fieldManager": map[string]any{"type": "string"},
+				},
+				"required": []any{"yaml"},
+			},
+		},
+		{
+			"name":        "datum_apply_crd",
+			"description": "Create or update against the API server (kubectl-apply-style create/update fallback; no dry-run).",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"yaml":         map[string]any{"type": "string"},
+					"project":      map[string]any{"type": "string"},
+					"fieldManager": map[string]any{"type": "string"},
+				},
+				"required": []any{"yaml"},
+			},
+		},
+		{
+			"name":        "datum_diff_crd",
+			"description": "Server-side apply dry-run diff against the live object (kubectl-diff-style); reports the unified diff, changed managed-fields owners, and SSA conflicts.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"yaml":         map[string]any{"type": "string"},
+					"project":      map[string]any{"type": "string"},
+					"fieldManager": map[string]any{"type": "string"},
 				},
 				"required": []any{"yaml"},
 			},
 		},
+		{
+			"name":        "datum_get_schema",
+			"description": "Return the OpenAPI v3 schema fragment for a known apiVersion/kind, optionally converted to draft-07 JSON Schema (format: \"openapi\" [default] or \"draft-07\"); preserves x-kubernetes-* extensions.",
+			"inputSchema": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"apiVersion": map[string]any{"type": "string"},
+					"kind":       map[string]any{"type": "string"},
+					"format":     map[string]any{"type": "string", "enum": []any{"openapi", "draft-07"}},
+				},
+				"required": []any{"apiVersion", "kind"},
+			},
+		},
 		{
 			"name":        "datum_refresh_discovery",
 			"description": "Refresh the OpenAPI discovery cache.",
 			"inputSchema": map[string]any{"type": "object", "properties": map[string]any{}, "required": []any{}},
 		},
+		{
+			"name":        "datum_cache_stats",
+			"description": "Return hit/miss/eviction counts for the on-disk HTTP response cache, keyed by control-plane host.",
+			"inputSchema": map[string]any{"type": "object", "properties": map[string]any{}, "required": []any{}},
+		},
 	}
 }
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/datum-cloud/datum-mcp/internal/api"
+	"github.com/datum-cloud/datum-mcp/internal/auth"
+)
+
+// dynamicRefreshInterval is how often Run/RunHTTP re-run discovery to pick
+// up CRDs installed after the server started.
+const dynamicRefreshInterval = 10 * time.Minute
+
+// staticToolNames are the hand-written tools already registered by
+// NewMCPServer; discovery never overrides them, even if a CRD happens to
+// share the plural name (e.g. a "domains" CRD), since the hand-written
+// tools carry environment-scoping and other behavior discovery can't infer.
+var staticToolNames = map[string]bool{
+	"organizations": true,
+	"projects":      true,
+	"environments":  true,
+	"users":         true,
+	"accounts":      true,
+	"domains":       true,
+	"httpproxies":   true,
+	"apis":          true,
+	"templates":     true,
+}
+
+// dynamicResourceDef/-Version/-Group mirror the JSON shape produced by
+// api.ListResourceDefinitions, typed just enough to drive registration.
+type dynamicResourceDef struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Namespaced bool   `json:"namespaced"`
+}
+
+type dynamicGroupVersion struct {
+	Version   string               `json:"version"`
+	Resources []dynamicResourceDef `json:"resources"`
+}
+
+type dynamicGroup struct {
+	Group    string                `json:"group"`
+	Versions []dynamicGroupVersion `json:"versions"`
+}
+
+type dynamicResourceDefs struct {
+	Groups []dynamicGroup `json:"groups"`
+}
+
+// dynamicRegistry tracks the server being served, the project discovery
+// runs against, and which dynamic tool names are already registered, so
+// refreshDynamicTools (called from the periodic ticker and from the apis
+// tool's "refresh" action) can re-run discovery against the live server.
+type dynamicRegistry struct {
+	mu              sync.Mutex
+	server          *mcp.Server
+	project         string
+	staticToolsOnly bool
+	registered      map[string]bool
+}
+
+var activeDynamicRegistry *dynamicRegistry
+
+// setupDynamicTools wires discovery-driven tool registration into s: an
+// initial best-effort pass (skipped, not failed, if there's no active
+// project or auth yet) followed by a periodic refresh every
+// dynamicRefreshInterval until ctx is canceled. When staticToolsOnly is
+// true, only the hand-written tools from NewMCPServer are served and this
+// is a no-op.
+func setupDynamicTools(ctx context.Context, s *mcp.Server, staticToolsOnly bool) {
+	reg := &dynamicRegistry{server: s, staticToolsOnly: staticToolsOnly, registered: map[string]bool{}}
+	activeDynamicRegistry = reg
+	if staticToolsOnly {
+		return
+	}
+	if n, err := reg.refresh(ctx); err != nil {
+		log.Printf("datum-mcp: dynamic tool discovery skipped: %v", err)
+	} else if n > 0 {
+		log.Printf("datum-mcp: registered %d dynamic CRD tool(s)", n)
+	}
+	go func() {
+		ticker := time.NewTicker(dynamicRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if n, err := reg.refresh(ctx); err != nil {
+					log.Printf("datum-mcp: dynamic tool refresh failed: %v", err)
+				} else if n > 0 {
+					log.Printf("datum-mcp: registered %d new dynamic CRD tool(s)", n)
+				}
+			}
+		}
+	}()
+}
+
+// refresh re-runs discovery for the active project and registers one tool
+// per CRD not already covered by a static or previously-registered dynamic
+// tool. It returns how many new tools were added.
+func (reg *dynamicRegistry) refresh(ctx context.Context) (int, error) {
+	if _, err := auth.EnsureAuth(ctx); err != nil {
+		return 0, err
+	}
+	project, err := resolveProjectName(reg.project)
+	if err != nil {
+		return 0, err
+	}
+
+	var defs dynamicResourceDefs
+	if err := api.ListResourceDefinitions(ctx, project, &defs); err != nil {
+		return 0, err
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	count := 0
+	for _, g := range defs.Groups {
+		for _, v := range g.Versions {
+			for _, r := range v.Resources {
+				name := strings.ToLower(r.Name)
+				if name == "" || staticToolNames[name] || reg.registered[name] {
+					continue
+				}
+				tool := &mcp.Tool{
+					Name:        name,
+					Description: fmt.Sprintf("CRUD for %s (%s/%s %s), auto-registered from project discovery. Actions: list|get|create|update|delete|patch|watch.", r.Name, g.Group, v.Version, r.Kind),
+				}
+				if schema, err := dynamicBodySchema(ctx, project, g.Group, v.Version, r.Kind); err == nil {
+					tool.InputSchema = schema
+				}
+				mcp.AddTool(reg.server, tool, dynamicHandler(g.Group, r.Kind, r.Namespaced))
+				reg.registered[name] = true
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// dynamicBodySchema wraps RoutedInput's inferred schema with a body
+// property synthesized from the CRD's own (trimmed) OpenAPI schema, so
+// clients get typed hints for create/update instead of an opaque object.
+func dynamicBodySchema(ctx context.Context, project, group, version, kind string) (*jsonschema.Schema, error) {
+	base, err := jsonschema.For[RoutedInput](nil)
+	if err != nil {
+		return nil, err
+	}
+	var crdSchema map[string]any
+	if err := api.GetResourceDefinition(ctx, project, group, version, kind, true, &crdSchema); err != nil {
+		return base, nil
+	}
+	b, err := json.Marshal(crdSchema)
+	if err != nil {
+		return base, nil
+	}
+	var bodySchema jsonschema.Schema
+	if err := json.Unmarshal(b, &bodySchema); err != nil {
+		return base, nil
+	}
+	if base.Properties == nil {
+		base.Properties = map[string]*jsonschema.Schema{}
+	}
+	base.Properties["body"] = &bodySchema
+	return base, nil
+}
+
+// dynamicHandler returns a RoutedInput handler for group/kind, the same
+// list/get/create/update/delete/patch/watch dispatch toolDomains/
+// toolHTTPProxies hand-write, parameterized instead of hard-coded.
+func dynamicHandler(group, kind string, namespaced bool) func(context.Context, *mcp.CallToolRequest, RoutedInput) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, in RoutedInput) (*mcp.CallToolResult, any, error) {
+		if _, err := auth.EnsureAuth(ctx); err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		p, env, err := resolveScope(in.Project, in.Environment)
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		ns := ""
+		if namespaced {
+			ns = environmentNamespace(env)
+		}
+		cli, err := api.NewProjectControlPlaneClient(ctx, p, nil)
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		switch strings.ToLower(string(in.Action)) {
+		case string(ActionList):
+			list, err := api.FetchList(ctx, cli, group, kind, ns)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true}, nil, err
+			}
+			b, _ := json.MarshalIndent(list, "", "  ")
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, list, nil
+		case string(ActionGet):
+			if in.ID == "" {
+				return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: id is required")
+			}
+			obj, err := api.FetchObject(ctx, cli, group, kind, ns, in.ID)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true}, nil, err
+			}
+			b, _ := json.MarshalIndent(obj, "", "  ")
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, obj, nil
+		case string(ActionCreate):
+			obj, err := api.CreateObject(ctx, cli, group, kind, ns, withEnvironmentRef(in.Body, env))
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true}, nil, err
+			}
+			b, _ := json.MarshalIndent(obj, "", "  ")
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, obj, nil
+		case string(ActionUpdate):
+			if in.ID == "" {
+				return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: id is required")
+			}
+			obj, err := api.UpdateObjectSpec(ctx, cli, group, kind, ns, in.ID, withEnvironmentRef(in.Body, env))
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true}, nil, err
+			}
+			b, _ := json.MarshalIndent(obj, "", "  ")
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, obj, nil
+		case string(ActionDelete):
+			if in.ID == "" {
+				return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: id is required")
+			}
+			if err := api.DeleteObject(ctx, cli, group, kind, ns, in.ID); err != nil {
+				return &mcp.CallToolResult{IsError: true}, nil, err
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "deleted"}}}, map[string]string{"deleted": in.ID}, nil
+		case string(ActionPatch):
+			obj, err := patchObject(ctx, cli, group, kind, ns, in)
+			if err != nil {
+				return &mcp.CallToolResult{IsError: true}, nil, err
+			}
+			b, _ := json.MarshalIndent(obj, "", "  ")
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, obj, nil
+		case string(ActionWatch):
+			return watchAction(ctx, req, cli, group, kind, ns)
+		default:
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("unsupported %s action: %s", kind, in.Action)
+		}
+	}
+}
+
+// refreshDynamicTools re-runs discovery against the server's active dynamic
+// registry, used by the apis tool's {"action":"refresh"}. It reports how
+// many new tools were registered, or an error if no dynamic registry is
+// active (e.g. the server was started with --static-tools-only).
+func refreshDynamicTools(ctx context.Context) (int, error) {
+	if activeDynamicRegistry == nil {
+		return 0, fmt.Errorf("dynamic tool registration is disabled (server started with --static-tools-only)")
+	}
+	if activeDynamicRegistry.staticToolsOnly {
+		return 0, fmt.Errorf("dynamic tool registration is disabled (server started with --static-tools-only)")
+	}
+	return activeDynamicRegistry.refresh(ctx)
+}
@@ -14,9 +14,12 @@ import (
 	"github.com/datum-cloud/datum-mcp/internal/api"
 	"github.com/datum-cloud/datum-mcp/internal/auth"
 	"github.com/datum-cloud/datum-mcp/internal/authutil"
+	"github.com/datum-cloud/datum-mcp/internal/environment"
 	"github.com/datum-cloud/datum-mcp/internal/org"
 	"github.com/datum-cloud/datum-mcp/internal/project"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ProjectSetInput struct {
@@ -31,17 +34,113 @@ const (
 	ActionCreate Action = "create"
 	ActionUpdate Action = "update"
 	ActionDelete Action = "delete"
+	ActionPatch  Action = "patch"
+	ActionWatch  Action = "watch"
 )
 
 type RoutedInput struct {
 	// Optional per-request project override; if empty, uses active project.
 	Project string `json:"project,omitempty"`
-	// Action: one of list|get|create|update|delete
+	// Optional per-request environment override; if empty, uses active
+	// environment. Environments are optional: when neither is set, resources
+	// fall back to the pre-environments "default" namespace.
+	Environment string `json:"environment,omitempty"`
+	// Action: one of list|get|create|update|delete|patch|watch
 	Action Action `json:"action"`
-	// ID required for get/update/delete
+	// ID required for get/update/delete/patch
 	ID string `json:"id,omitempty"`
 	// Body is the request payload for create/update
 	Body map[string]any `json:"body,omitempty"`
+	// PatchType selects the patch semantics for action=patch: one of
+	// merge|json|strategic|apply. Defaults to merge.
+	PatchType string `json:"patchType,omitempty"`
+	// FieldManager is required when PatchType is "apply" and recorded in
+	// managedFields for the other patch types.
+	FieldManager string `json:"fieldManager,omitempty"`
+	// Force takes ownership of conflicting fields; only meaningful when
+	// PatchType is "apply".
+	Force bool `json:"force,omitempty"`
+}
+
+// patchTypeFor maps the MCP-facing patchType string onto a
+// k8s.io/apimachinery/pkg/types.PatchType.
+func patchTypeFor(s string) (types.PatchType, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "merge":
+		return types.MergePatchType, nil
+	case "json":
+		return types.JSONPatchType, nil
+	case "strategic":
+		return types.StrategicMergePatchType, nil
+	case "apply":
+		return types.ApplyPatchType, nil
+	default:
+		return "", fmt.Errorf("unsupported patchType %q (use merge|json|strategic|apply)", s)
+	}
+}
+
+// patchObject runs action=patch for a RoutedInput-backed tool against the
+// given GVK/namespace, returning a conflict-tagged error when SSA reports
+// ownership conflicts so callers can retry with force=true.
+func patchObject(ctx context.Context, cli ctrlclient.Client, group, kind, namespace string, in RoutedInput) (*unstructured.Unstructured, error) {
+	if in.ID == "" {
+		return nil, fmt.Errorf("invalid params: id is required")
+	}
+	pt, err := patchTypeFor(in.PatchType)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(in.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body: %w", err)
+	}
+	obj, err := api.PatchObject(ctx, cli, group, kind, namespace, in.ID, pt, payload, api.PatchOptions{
+		FieldManager: in.FieldManager,
+		Force:        in.Force,
+	})
+	if err != nil {
+		if api.IsConflict(err) {
+			return nil, fmt.Errorf("patch conflict (retry with force=true to take ownership): %w", err)
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+// watchAction streams ADDED/MODIFIED/DELETED events for group/kind/namespace
+// as MCP progress notifications on req's session, relying on api.WatchList
+// to relist and resume across 410 Gone so the stream stays open; changes
+// made between the expiry and the relist are not delivered as events. It
+// blocks until ctx is canceled (the client stops listening) or the event
+// stream closes, at which point the call resolves with a summary of how
+// many events were delivered.
+func watchAction(ctx context.Context, req *mcp.CallToolRequest, cli ctrlclient.Client, group, kind, namespace string) (*mcp.CallToolResult, any, error) {
+	events, err := api.WatchList(ctx, cli, group, kind, namespace)
+	if err != nil {
+		return &mcp.CallToolResult{IsError: true}, nil, err
+	}
+	token := req.Params.GetProgressToken()
+	delivered := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "watch ended"}}}, map[string]any{"events": delivered}, nil
+		case ev, ok := <-events:
+			if !ok {
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "watch ended"}}}, map[string]any{"events": delivered}, nil
+			}
+			delivered++
+			if token == nil {
+				continue
+			}
+			b, _ := json.Marshal(map[string]any{"type": ev.Type, "object": ev.Object})
+			_ = req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+				ProgressToken: token,
+				Progress:      float64(delivered),
+				Message:       string(b),
+			})
+		}
+	}
 }
 
 type APIInfoInput struct {
@@ -51,6 +150,7 @@ type APIInfoInput struct {
 	Version string `json:"version,omitempty"`
 	Kind    string `json:"kind,omitempty"`
 	Action  string `json:"action,omitempty"`
+	Trim    bool   `json:"trim,omitempty"`
 }
 
 type OrgInput struct {
@@ -74,6 +174,18 @@ type UsersInput struct {
 	Org    string `json:"org,omitempty"`
 }
 
+type AccountsInput struct {
+	Action  string `json:"action"`
+	Email   string `json:"email,omitempty"`
+	Project string `json:"project,omitempty"`
+}
+
+type EnvironmentsInput struct {
+	Action  string         `json:"action"`
+	Project string         `json:"project,omitempty"`
+	Body    map[string]any `json:"body,omitempty"`
+}
+
 func resolveProjectName(override string) (string, error) {
 	if override != "" {
 		return override, nil
@@ -85,6 +197,49 @@ func resolveProjectName(override string) (string, error) {
 	return p, nil
 }
 
+// resolveScope extends resolveProjectName with an environment: project
+// resolution is unchanged, and env falls back to the active environment
+// when the request didn't override it. Unlike project, env may come back
+// empty - environments are opt-in, and callers fall back to the
+// pre-environments "default" namespace in that case.
+func resolveScope(projectOverride, envOverride string) (string, string, error) {
+	p, err := resolveProjectName(projectOverride)
+	if err != nil {
+		return "", "", err
+	}
+	if envOverride != "" {
+		return p, envOverride, nil
+	}
+	e, _ := environment.GetActive()
+	return p, e, nil
+}
+
+// environmentNamespace maps a (possibly empty) environment name onto the
+// namespace environment-scoped resources live in, preserving the
+// pre-environments "default" namespace when no environment is active.
+func environmentNamespace(env string) string {
+	if env == "" {
+		return "default"
+	}
+	return env
+}
+
+// withEnvironmentRef stamps spec.environmentRef.name onto a create/update
+// body when an environment is active, leaving the body untouched when env
+// is empty so unscoped callers see no behavior change.
+func withEnvironmentRef(body map[string]any, env string) map[string]any {
+	if env == "" || body == nil {
+		return body
+	}
+	spec, _ := body["spec"].(map[string]any)
+	if spec == nil {
+		spec = map[string]any{}
+	}
+	spec["environmentRef"] = map[string]any{"name": env}
+	body["spec"] = spec
+	return body
+}
+
 func resolveOrgName(override string) (string, error) {
 	if override != "" {
 		return override, nil
@@ -118,7 +273,7 @@ func toolOrganizationMemberships(ctx context.Context, _ *mcp.CallToolRequest, in
 			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("failed to determine user ID: %w", err)
 		}
 	}
-	ucli, err := api.NewUserControlPlaneClient(ctx, userID)
+	ucli, err := api.NewUserControlPlaneClient(ctx, userID, nil)
 	if err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
@@ -178,7 +333,7 @@ func toolProjects(ctx context.Context, _ *mcp.CallToolRequest, in ProjectsInput)
 	if err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
-	cli, err := api.NewOrgControlPlaneClient(ctx, orgName)
+	cli, err := api.NewOrgControlPlaneClient(ctx, orgName, nil)
 	if err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
@@ -231,6 +386,91 @@ func toolProjects(ctx context.Context, _ *mcp.CallToolRequest, in ProjectsInput)
 	}
 }
 
+// Environments tool: list/get/set/create/delete environments (dev/staging/
+// prod scoping) under a project. create/set operate in the project given
+// by 'project' or the active project; set validates the name against the
+// project's environment list before persisting it, matching how
+// toolProjects validates names against the org's project list.
+// - {"action":"list","project":"proj-1"}
+// - {"action":"create","project":"proj-1","body":{"name":"staging"}}
+// - {"action":"set","body":{"name":"staging"}}
+// - {"action":"get"}
+// - {"action":"delete","body":{"name":"staging"}}
+func toolEnvironments(ctx context.Context, _ *mcp.CallToolRequest, in EnvironmentsInput) (*mcp.CallToolResult, any, error) {
+	a := strings.ToLower(strings.TrimSpace(in.Action))
+	if a == "" {
+		a = "list"
+	}
+	if a == "get" {
+		e, _ := environment.GetActive()
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: e}}}, map[string]string{"environment": e}, nil
+	}
+	if _, err := auth.EnsureAuth(ctx); err != nil {
+		return &mcp.CallToolResult{IsError: true}, nil, err
+	}
+	p, err := resolveProjectName(in.Project)
+	if err != nil {
+		return &mcp.CallToolResult{IsError: true}, nil, err
+	}
+	cli, err := api.NewProjectControlPlaneClient(ctx, p, nil)
+	if err != nil {
+		return &mcp.CallToolResult{IsError: true}, nil, err
+	}
+	switch a {
+	case "list":
+		list, err := api.FetchList(ctx, cli, "resourcemanager.miloapis.com", "Environment", "")
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		b, _ := json.MarshalIndent(list, "", "  ")
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, list, nil
+	case "create":
+		if in.Body == nil {
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: body is required for create")
+		}
+		obj, err := api.CreateObject(ctx, cli, "resourcemanager.miloapis.com", "Environment", "", in.Body)
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		b, _ := json.MarshalIndent(obj, "", "  ")
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, obj, nil
+	case "set":
+		name, _ := in.Body["name"].(string)
+		if name == "" {
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: body.name is required")
+		}
+		list, err := api.FetchList(ctx, cli, "resourcemanager.miloapis.com", "Environment", "")
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		found := false
+		for _, it := range list.Items {
+			if strings.EqualFold(it.GetName(), name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("environment %q not found in project %q", name, p)
+		}
+		if err := environment.SetActive(name); err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "active environment set"}}}, map[string]string{"environment": name}, nil
+	case "delete":
+		name, _ := in.Body["name"].(string)
+		if name == "" {
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: body.name is required")
+		}
+		if err := api.DeleteObject(ctx, cli, "resourcemanager.miloapis.com", "Environment", "", name); err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "deleted"}}}, map[string]string{"deleted": name}, nil
+	default:
+		return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("unsupported environments action: %s", in.Action)
+	}
+}
+
 // Users tool: list users in an organization (requires active org or 'org')
 // - {"action":"list","org":"org-1"}
 func toolUsers(ctx context.Context, _ *mcp.CallToolRequest, in UsersInput) (*mcp.CallToolResult, any, error) {
@@ -245,7 +485,7 @@ func toolUsers(ctx context.Context, _ *mcp.CallToolRequest, in UsersInput) (*mcp
 	if err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
-	cli, err := api.NewOrgControlPlaneClient(ctx, orgName)
+	cli, err := api.NewOrgControlPlaneClient(ctx, orgName, nil)
 	if err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
@@ -262,6 +502,79 @@ func toolUsers(ctx context.Context, _ *mcp.CallToolRequest, in UsersInput) (*mcp
 	}
 }
 
+// Accounts tool: manage the multiple Datum identities stored in the
+// keyring and, optionally, pin one of them to a specific project so an
+// agent driving several orgs from one session doesn't need env-var
+// juggling or a global account switch per call. Actions: list|get|switch|
+// remove|bind|unbind.
+// - {"action":"list"}
+// - {"action":"get"}
+// - {"action":"switch","email":"user@example.com"}
+// - {"action":"remove","email":"user@example.com"}
+// - {"action":"bind","project":"proj-1","email":"user@example.com"}
+// - {"action":"unbind","project":"proj-1"}
+func toolAccounts(ctx context.Context, _ *mcp.CallToolRequest, in AccountsInput) (*mcp.CallToolResult, any, error) {
+	a := strings.ToLower(strings.TrimSpace(in.Action))
+	if a == "" {
+		a = "list"
+	}
+	switch a {
+	case "list":
+		accounts, err := authutil.ListAccounts()
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		b, _ := json.MarshalIndent(accounts, "", "  ")
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, accounts, nil
+	case "get":
+		active, err := authutil.GetActiveAccount()
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		b, _ := json.MarshalIndent(active, "", "  ")
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, active, nil
+	case "switch":
+		email := strings.TrimSpace(in.Email)
+		if email == "" {
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: email is required")
+		}
+		if err := authutil.SwitchAccount(email); err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "active account set"}}}, map[string]string{"email": email}, nil
+	case "remove":
+		email := strings.TrimSpace(in.Email)
+		if email == "" {
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: email is required")
+		}
+		if err := authutil.RemoveAccount(email); err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "account removed"}}}, map[string]string{"email": email}, nil
+	case "bind":
+		p := strings.TrimSpace(in.Project)
+		email := strings.TrimSpace(in.Email)
+		if p == "" || email == "" {
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: project and email are required")
+		}
+		if err := authutil.SetProjectAccount(p, email); err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "project account bound"}}}, map[string]string{"project": p, "email": email}, nil
+	case "unbind":
+		p := strings.TrimSpace(in.Project)
+		if p == "" {
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: project is required")
+		}
+		if err := authutil.SetProjectAccount(p, ""); err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "project account unbound"}}}, map[string]string{"project": p}, nil
+	default:
+		return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("unsupported accounts action: %s", in.Action)
+	}
+}
+
 // Domains tool supports: list|get|create|update|delete
 // Examples:
 // - {"action":"list","project":"my-proj"}
@@ -269,21 +582,22 @@ func toolUsers(ctx context.Context, _ *mcp.CallToolRequest, in UsersInput) (*mcp
 // - {"action":"create","body":{...}}
 // - {"action":"update","id":"domain-1","body":{...}}
 // - {"action":"delete","id":"domain-1"}
-func toolDomains(ctx context.Context, _ *mcp.CallToolRequest, in RoutedInput) (*mcp.CallToolResult, any, error) {
+func toolDomains(ctx context.Context, req *mcp.CallToolRequest, in RoutedInput) (*mcp.CallToolResult, any, error) {
 	if _, err := auth.EnsureAuth(ctx); err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
-	p, err := resolveProjectName(in.Project)
+	p, env, err := resolveScope(in.Project, in.Environment)
 	if err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
-	cli, err := api.NewProjectControlPlaneClient(ctx, p)
+	ns := environmentNamespace(env)
+	cli, err := api.NewProjectControlPlaneClient(ctx, p, nil)
 	if err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
 	switch strings.ToLower(string(in.Action)) {
 	case string(ActionList):
-		list, err := api.FetchList(ctx, cli, "networking.datumapis.com", "Domain", "default")
+		list, err := api.FetchList(ctx, cli, "networking.datumapis.com", "Domain", ns)
 		if err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
@@ -293,14 +607,14 @@ func toolDomains(ctx context.Context, _ *mcp.CallToolRequest, in RoutedInput) (*
 		if in.ID == "" {
 			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: id is required")
 		}
-		obj, err := api.FetchObject(ctx, cli, "networking.datumapis.com", "Domain", "default", in.ID)
+		obj, err := api.FetchObject(ctx, cli, "networking.datumapis.com", "Domain", ns, in.ID)
 		if err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
 		b, _ := json.MarshalIndent(obj, "", "  ")
 		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, obj, nil
 	case string(ActionCreate):
-		obj, err := api.CreateObject(ctx, cli, "networking.datumapis.com", "Domain", "default", in.Body)
+		obj, err := api.CreateObject(ctx, cli, "networking.datumapis.com", "Domain", ns, withEnvironmentRef(in.Body, env))
 		if err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
@@ -310,7 +624,7 @@ func toolDomains(ctx context.Context, _ *mcp.CallToolRequest, in RoutedInput) (*
 		if in.ID == "" {
 			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: id is required")
 		}
-		obj, err := api.UpdateObjectSpec(ctx, cli, "networking.datumapis.com", "Domain", "default", in.ID, in.Body)
+		obj, err := api.UpdateObjectSpec(ctx, cli, "networking.datumapis.com", "Domain", ns, in.ID, withEnvironmentRef(in.Body, env))
 		if err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
@@ -320,10 +634,19 @@ func toolDomains(ctx context.Context, _ *mcp.CallToolRequest, in RoutedInput) (*
 		if in.ID == "" {
 			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: id is required")
 		}
-		if err := api.DeleteObject(ctx, cli, "networking.datumapis.com", "Domain", "default", in.ID); err != nil {
+		if err := api.DeleteObject(ctx, cli, "networking.datumapis.com", "Domain", ns, in.ID); err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
 		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "deleted"}}}, map[string]string{"deleted": in.ID}, nil
+	case string(ActionPatch):
+		obj, err := patchObject(ctx, cli, "networking.datumapis.com", "Domain", ns, in)
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		b, _ := json.MarshalIndent(obj, "", "  ")
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, obj, nil
+	case string(ActionWatch):
+		return watchAction(ctx, req, cli, "networking.datumapis.com", "Domain", ns)
 	default:
 		return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("unsupported domains action: %s", in.Action)
 	}
@@ -336,22 +659,23 @@ func toolDomains(ctx context.Context, _ *mcp.CallToolRequest, in RoutedInput) (*
 // - {"action":"create","body":{...}}
 // - {"action":"update","id":"proxy-1","body":{...}}
 // - {"action":"delete","id":"proxy-1"}
-func toolHTTPProxies(ctx context.Context, _ *mcp.CallToolRequest, in RoutedInput) (*mcp.CallToolResult, any, error) {
+func toolHTTPProxies(ctx context.Context, req *mcp.CallToolRequest, in RoutedInput) (*mcp.CallToolResult, any, error) {
 	_, err := auth.EnsureAuth(ctx)
 	if err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
-	p, err := resolveProjectName(in.Project)
+	p, env, err := resolveScope(in.Project, in.Environment)
 	if err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
-	cli, err := api.NewProjectControlPlaneClient(ctx, p)
+	ns := environmentNamespace(env)
+	cli, err := api.NewProjectControlPlaneClient(ctx, p, nil)
 	if err != nil {
 		return &mcp.CallToolResult{IsError: true}, nil, err
 	}
 	switch strings.ToLower(string(in.Action)) {
 	case string(ActionList):
-		list, err := api.FetchList(ctx, cli, "networking.datumapis.com", "HTTPProxy", "default")
+		list, err := api.FetchList(ctx, cli, "networking.datumapis.com", "HTTPProxy", ns)
 		if err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
@@ -361,14 +685,14 @@ func toolHTTPProxies(ctx context.Context, _ *mcp.CallToolRequest, in RoutedInput
 		if in.ID == "" {
 			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: id is required")
 		}
-		obj, err := api.FetchObject(ctx, cli, "networking.datumapis.com", "HTTPProxy", "default", in.ID)
+		obj, err := api.FetchObject(ctx, cli, "networking.datumapis.com", "HTTPProxy", ns, in.ID)
 		if err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
 		b, _ := json.MarshalIndent(obj, "", "  ")
 		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, obj, nil
 	case string(ActionCreate):
-		obj, err := api.CreateObject(ctx, cli, "networking.datumapis.com", "HTTPProxy", "default", in.Body)
+		obj, err := api.CreateObject(ctx, cli, "networking.datumapis.com", "HTTPProxy", ns, withEnvironmentRef(in.Body, env))
 		if err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
@@ -378,7 +702,7 @@ func toolHTTPProxies(ctx context.Context, _ *mcp.CallToolRequest, in RoutedInput
 		if in.ID == "" {
 			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: id is required")
 		}
-		obj, err := api.UpdateObjectSpec(ctx, cli, "networking.datumapis.com", "HTTPProxy", "default", in.ID, in.Body)
+		obj, err := api.UpdateObjectSpec(ctx, cli, "networking.datumapis.com", "HTTPProxy", ns, in.ID, withEnvironmentRef(in.Body, env))
 		if err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
@@ -388,10 +712,19 @@ func toolHTTPProxies(ctx context.Context, _ *mcp.CallToolRequest, in RoutedInput
 		if in.ID == "" {
 			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: id is required")
 		}
-		if err := api.DeleteObject(ctx, cli, "networking.datumapis.com", "HTTPProxy", "default", in.ID); err != nil {
+		if err := api.DeleteObject(ctx, cli, "networking.datumapis.com", "HTTPProxy", ns, in.ID); err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
 		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "deleted"}}}, map[string]string{"deleted": in.ID}, nil
+	case string(ActionPatch):
+		obj, err := patchObject(ctx, cli, "networking.datumapis.com", "HTTPProxy", ns, in)
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		b, _ := json.MarshalIndent(obj, "", "  ")
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, obj, nil
+	case string(ActionWatch):
+		return watchAction(ctx, req, cli, "networking.datumapis.com", "HTTPProxy", ns)
 	default:
 		return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("unsupported httpproxies action: %s", in.Action)
 	}
@@ -429,11 +762,17 @@ func toolAPIs(ctx context.Context, _ *mcp.CallToolRequest, in APIInfoInput) (*mc
 			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("invalid params: group and version are required for get")
 		}
 		var out any
-		if err := api.GetResourceDefinition(ctx, p, g, v, strings.TrimSpace(in.Kind), &out); err != nil {
+		if err := api.GetResourceDefinition(ctx, p, g, v, strings.TrimSpace(in.Kind), in.Trim, &out); err != nil {
 			return &mcp.CallToolResult{IsError: true}, nil, err
 		}
 		b, _ := json.MarshalIndent(out, "", "  ")
 		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, out, nil
+	case "refresh":
+		n, err := refreshDynamicTools(ctx)
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "dynamic tools refreshed"}}}, map[string]int{"registered": n}, nil
 	default:
 		return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("unsupported apis action: %s", in.Action)
 	}
@@ -444,23 +783,33 @@ func NewMCPServer() *mcp.Server {
 	s := mcp.NewServer(&mcp.Implementation{Name: "datum-mcp", Version: "0.1.0"}, nil)
 	mcp.AddTool(s, &mcp.Tool{Name: "organizations", Description: "Manage organization context. Actions: list|get|set (name)."}, toolOrganizationMemberships)
 	mcp.AddTool(s, &mcp.Tool{Name: "projects", Description: "Manage projects. Actions: list|get|create|set. list/create require active org or 'org'; set uses body.name."}, toolProjects)
+	mcp.AddTool(s, &mcp.Tool{Name: "environments", Description: "Manage per-project environments (dev/staging/prod scoping). Actions: list|get|create|set|delete. list/create/set/delete require active project or 'project'; set/delete use body.name. When an environment is active, domains/httpproxies resources are scoped to its namespace and stamped with spec.environmentRef."}, toolEnvironments)
 	mcp.AddTool(s, &mcp.Tool{Name: "users", Description: "List users under the active org or 'org'. Actions: list."}, toolUsers)
-	mcp.AddTool(s, &mcp.Tool{Name: "domains", Description: "CRUD for domains. Actions: list|get|create|update|delete. Fields: project (optional), id (for get/update/delete), body (for create/update)."}, toolDomains)
-	mcp.AddTool(s, &mcp.Tool{Name: "httpproxies", Description: "CRUD for HTTP proxies. Actions: list|get|create|update|delete. Fields: project (optional), id (for get/update/delete), body (for create/update)."}, toolHTTPProxies)
-	mcp.AddTool(s, &mcp.Tool{Name: "apis", Description: "List/get CRDs under the current project. Actions: list|get. Fields: project (optional), name (for get)."}, toolAPIs)
+	mcp.AddTool(s, &mcp.Tool{Name: "accounts", Description: "Manage stored Datum identities. Actions: list|get|switch|remove (email)|bind|unbind (project, email). get returns the active account; bind pins a project to a specific account so project-scoped tools use it instead of the active account."}, toolAccounts)
+	mcp.AddTool(s, &mcp.Tool{Name: "domains", Description: "CRUD for domains. Actions: list|get|create|update|delete|patch|watch. Fields: project (optional), environment (optional; scopes to that environment's namespace and stamps spec.environmentRef), id (for get/update/delete/patch), body (for create/update/patch), patchType (merge|json|strategic|apply, for patch), fieldManager/force (for patch=apply). watch streams ADDED/MODIFIED/DELETED as progress notifications until the call is canceled."}, toolDomains)
+	mcp.AddTool(s, &mcp.Tool{Name: "httpproxies", Description: "CRUD for HTTP proxies. Actions: list|get|create|update|delete|patch|watch. Fields: project (optional), environment (optional; scopes to that environment's namespace and stamps spec.environmentRef), id (for get/update/delete/patch), body (for create/update/patch), patchType (merge|json|strategic|apply, for patch), fieldManager/force (for patch=apply). watch streams ADDED/MODIFIED/DELETED as progress notifications until the call is canceled."}, toolHTTPProxies)
+	mcp.AddTool(s, &mcp.Tool{Name: "apis", Description: "List/get CRDs under the current project. Actions: list|get|refresh. Fields: project (optional), name (for get), trim (bool, return a reduced JSON-schema-lite view with $ref inlined instead of the raw OpenAPI component). refresh re-runs discovery and registers any new CRDs as dynamic tools (returns an error if the server was started with --static-tools-only)."}, toolAPIs)
+	mcp.AddTool(s, &mcp.Tool{Name: "templates", Description: "Install curated bundles of Datum resources from a slug catalog. Actions: list|get|install. Fields: slug (for get/install), project (optional), body (template params for install, e.g. DomainName/BackendEndpoint)."}, toolTemplates)
 	return s
 }
 
-// Run starts the server over stdio (default transport).
-func Run(ctx context.Context) error {
+// Run starts the server over stdio (default transport). When
+// staticToolsOnly is false, a best-effort discovery pass registers one tool
+// per CRD found under the active project in addition to the hand-written
+// tools, refreshed periodically and on-demand via {"action":"refresh"} to
+// apis.
+func Run(ctx context.Context, staticToolsOnly bool) error {
 	s := NewMCPServer()
+	setupDynamicTools(ctx, s, staticToolsOnly)
 	log.Printf("datum-mcp running (stdio)")
 	return s.Run(ctx, &mcp.StdioTransport{})
 }
 
-// RunHTTP starts the server using the streamable HTTP transport at addr (e.g., "localhost:9000").
-func RunHTTP(ctx context.Context, addr string) error {
+// RunHTTP starts the server using the streamable HTTP transport at addr
+// (e.g., "localhost:9000"). See Run for staticToolsOnly.
+func RunHTTP(ctx context.Context, addr string, staticToolsOnly bool) error {
 	s := NewMCPServer()
+	setupDynamicTools(ctx, s, staticToolsOnly)
 	handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server { return s }, nil)
 	log.Printf("datum-mcp listening (http) on %s", addr)
 	return http.ListenAndServe(addr, handler)
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"gopkg.in/yaml.v3"
+
+	"github.com/datum-cloud/datum-mcp/internal/api"
+	"github.com/datum-cloud/datum-mcp/internal/auth"
+)
+
+// templateObject is one unstructured resource in a bundle. Manifest is a
+// text/template source rendered against the caller's Body before being
+// decoded as YAML and dispatched through api.CreateObject.
+type templateObject struct {
+	Group    string
+	Kind     string
+	Manifest string
+}
+
+// templateBundle is a named, curated set of objects that stand up a common
+// Datum pattern (e.g. a Domain fronted by an HTTPProxy) from one tool call.
+type templateBundle struct {
+	Description string
+	Objects     []templateObject
+}
+
+// templateCatalog is the fixed set of installable bundles. Additions here
+// should stay small and composable; anything bespoke belongs in the
+// caller's own manifests via the domains/httpproxies tools instead.
+var templateCatalog = map[string]templateBundle{
+	"domain-with-httpproxy": {
+		Description: "A Domain plus an HTTPProxy that routes traffic to it. Params: DomainName, BackendEndpoint.",
+		Objects: []templateObject{
+			{
+				Group: "networking.datumapis.com",
+				Kind:  "Domain",
+				Manifest: `
+apiVersion: networking.datumapis.com/v1alpha1
+kind: Domain
+metadata:
+  name: {{.DomainName}}
+spec:
+  domainName: {{.DomainName}}
+`,
+			},
+			{
+				Group: "networking.datumapis.com",
+				Kind:  "HTTPProxy",
+				Manifest: `
+apiVersion: networking.datumapis.com/v1alpha1
+kind: HTTPProxy
+metadata:
+  name: {{.DomainName}}
+spec:
+  rules:
+    - backends:
+        - endpoint: {{.BackendEndpoint}}
+      matches:
+        - path:
+            pathPrefix: /
+  hostnames:
+    - {{.DomainName}}
+`,
+			},
+		},
+	},
+	"httpproxy-basic": {
+		Description: "A standalone HTTPProxy with a single catch-all backend. Params: Name, BackendEndpoint.",
+		Objects: []templateObject{
+			{
+				Group: "networking.datumapis.com",
+				Kind:  "HTTPProxy",
+				Manifest: `
+apiVersion: networking.datumapis.com/v1alpha1
+kind: HTTPProxy
+metadata:
+  name: {{.Name}}
+spec:
+  rules:
+    - backends:
+        - endpoint: {{.BackendEndpoint}}
+      matches:
+        - path:
+            pathPrefix: /
+`,
+			},
+		},
+	},
+}
+
+// renderManifest executes the object's template against params and decodes
+// the result as a single YAML document.
+func (o templateObject) renderManifest(params map[string]any) (map[string]any, error) {
+	tmpl, err := template.New(o.Kind).Option("missingkey=error").Parse(o.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template for %s: %w", o.Kind, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", o.Kind, err)
+	}
+	var out map[string]any
+	if err := yaml.Unmarshal(buf.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("rendered %s is not valid YAML: %w", o.Kind, err)
+	}
+	return out, nil
+}
+
+type TemplatesInput struct {
+	Action  string         `json:"action"`
+	Slug    string         `json:"slug,omitempty"`
+	Project string         `json:"project,omitempty"`
+	Body    map[string]any `json:"body,omitempty"`
+}
+
+// Templates tool: install curated bundles of Datum resources from a slug
+// catalog, modeled on the 1-click-application pattern. Actions: list|get|
+// install.
+// - {"action":"list"}
+// - {"action":"get","slug":"domain-with-httpproxy"}
+// - {"action":"install","slug":"domain-with-httpproxy","body":{"DomainName":"example.com","BackendEndpoint":"backend.default.svc:8080"}}
+func toolTemplates(ctx context.Context, _ *mcp.CallToolRequest, in TemplatesInput) (*mcp.CallToolResult, any, error) {
+	a := strings.ToLower(strings.TrimSpace(in.Action))
+	if a == "" {
+		a = "list"
+	}
+	switch a {
+	case "list":
+		slugs := make([]string, 0, len(templateCatalog))
+		for slug := range templateCatalog {
+			slugs = append(slugs, slug)
+		}
+		sort.Strings(slugs)
+		out := make([]map[string]string, 0, len(slugs))
+		for _, slug := range slugs {
+			out = append(out, map[string]string{"slug": slug, "description": templateCatalog[slug].Description})
+		}
+		b, _ := json.MarshalIndent(out, "", "  ")
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, out, nil
+	case "get":
+		slug := strings.TrimSpace(in.Slug)
+		bundle, ok := templateCatalog[slug]
+		if !ok {
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("unknown template slug %q", slug)
+		}
+		b, _ := json.MarshalIndent(bundle, "", "  ")
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, bundle, nil
+	case "install":
+		slug := strings.TrimSpace(in.Slug)
+		bundle, ok := templateCatalog[slug]
+		if !ok {
+			return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("unknown template slug %q", slug)
+		}
+		if _, err := auth.EnsureAuth(ctx); err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		p, err := resolveProjectName(in.Project)
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		cli, err := api.NewProjectControlPlaneClient(ctx, p, nil)
+		if err != nil {
+			return &mcp.CallToolResult{IsError: true}, nil, err
+		}
+		created := make([]*templateObject, 0, len(bundle.Objects))
+		names := make([]string, 0, len(bundle.Objects))
+		rollback := func() {
+			for i := len(names) - 1; i >= 0; i-- {
+				_ = api.DeleteObject(ctx, cli, created[i].Group, created[i].Kind, "default", names[i])
+			}
+		}
+		results := make([]map[string]any, 0, len(bundle.Objects))
+		for i := range bundle.Objects {
+			obj := bundle.Objects[i]
+			rendered, err := obj.renderManifest(in.Body)
+			if err != nil {
+				rollback()
+				return &mcp.CallToolResult{IsError: true}, nil, err
+			}
+			createdObj, err := api.CreateObject(ctx, cli, obj.Group, obj.Kind, "default", rendered)
+			if err != nil {
+				rollback()
+				return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("failed to create %s (rolled back %d already-created object(s)): %w", obj.Kind, len(names), err)
+			}
+			created = append(created, &obj)
+			names = append(names, createdObj.GetName())
+			results = append(results, createdObj.Object)
+		}
+		b, _ := json.MarshalIndent(results, "", "  ")
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(b)}}}, results, nil
+	default:
+		return &mcp.CallToolResult{IsError: true}, nil, fmt.Errorf("unsupported templates action: %s", in.Action)
+	}
+}
@@ -0,0 +1,268 @@
+// Package watcher maintains shared, informer-backed local caches of
+// control-plane objects, so MCP resources can be read or subscribed to
+// without a round trip per call the way internal/api's one-shot client
+// factories and api.WatchList require. Unlike api.WatchList, a subscriber
+// gets both a synchronously-readable current list (the informer's local
+// store) and a stream of subsequent changes, which a subscribe-then-read
+// flow needs and a bare event stream can't provide on its own.
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/datum-cloud/datum-mcp/internal/api"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultResyncPeriod is how often an informer replays its entire local
+// store back through the event handlers as synthetic MODIFIED events, so a
+// subscriber that dropped an event under backpressure eventually self-heals
+// instead of drifting from the live object forever.
+const defaultResyncPeriod = 10 * time.Minute
+
+// queueSize bounds each subscriber's event channel; once full, the oldest
+// queued event is dropped in favor of the newest, the same coalescing
+// backpressure api.WatchList applies to its own subscribers.
+const queueSize = 64
+
+// GVR identifies one informer's target: the project-scoped group/version/
+// kind (and optional namespace) it lists and watches. It pins a concrete
+// Version rather than letting the RESTMapper pick one, since an informer
+// decodes every list/watch response against a single GVK for the lifetime
+// of its local store.
+type GVR struct {
+	Project   string
+	Group     string
+	Version   string
+	Kind      string
+	Namespace string
+}
+
+// Options configures one informer beyond its GVR.
+type Options struct {
+	// ResyncPeriod overrides defaultResyncPeriod when positive.
+	ResyncPeriod time.Duration
+	// LabelSelector and FieldSelector scope the informer's list/watch the
+	// same way they would a kubectl get/watch.
+	LabelSelector string
+	FieldSelector string
+}
+
+// ClientFactory builds the controller-runtime client an informer issues
+// its list/watch calls against. Callers pass a closure around
+// api.NewProjectControlPlaneClient (or equivalent) so Manager doesn't need
+// to know how project auth is resolved.
+type ClientFactory func(ctx context.Context, project string) (ctrlclient.Client, error)
+
+// Manager starts and shares one informer per distinct GVR plus label and
+// field selector across any number of subscribers, so N MCP clients
+// watching the same resource cost one upstream list/watch. ResyncPeriod is
+// not part of that key: a later subscriber shares the existing informer
+// with whatever resync period it was started with.
+type Manager struct {
+	mu        sync.Mutex
+	informers map[informerKey]*liveInformer
+}
+
+type informerKey struct {
+	gvr           GVR
+	labelSelector string
+	fieldSelector string
+}
+
+// NewManager returns an empty manager ready to Subscribe.
+func NewManager() *Manager {
+	return &Manager{informers: make(map[informerKey]*liveInformer)}
+}
+
+// liveInformer wraps one cache.SharedIndexInformer and fans its resource
+// events out to subscribers.
+type liveInformer struct {
+	informer cache.SharedIndexInformer
+	cancel   context.CancelFunc
+
+	mu          sync.Mutex
+	subscribers map[int]chan api.WatchEvent
+	nextID      int
+}
+
+// Subscribe starts (or reuses) the informer for gvr+opts and returns a
+// channel of coalesced ADDED/MODIFIED/DELETED events alongside the
+// informer's current cached list, so a caller can serve a read immediately
+// while also tracking future changes. Call the returned unsubscribe func to
+// stop receiving events; the informer itself keeps running for any other
+// subscriber and is torn down once the last one leaves.
+func (m *Manager) Subscribe(ctx context.Context, factory ClientFactory, gvr GVR, opts Options) (<-chan api.WatchEvent, []*unstructured.Unstructured, func(), error) {
+	key := informerKey{gvr: gvr, labelSelector: opts.LabelSelector, fieldSelector: opts.FieldSelector}
+
+	m.mu.Lock()
+	li, ok := m.informers[key]
+	if !ok {
+		var err error
+		li, err = newLiveInformer(ctx, factory, gvr, opts)
+		if err != nil {
+			m.mu.Unlock()
+			return nil, nil, nil, err
+		}
+		m.informers[key] = li
+	}
+	m.mu.Unlock()
+
+	li.mu.Lock()
+	id := li.nextID
+	li.nextID++
+	sub := make(chan api.WatchEvent, queueSize)
+	li.subscribers[id] = sub
+	li.mu.Unlock()
+
+	unsubscribe := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		li.mu.Lock()
+		delete(li.subscribers, id)
+		empty := len(li.subscribers) == 0
+		li.mu.Unlock()
+		if empty {
+			li.cancel()
+			delete(m.informers, key)
+		}
+	}
+	return sub, li.list(), unsubscribe, nil
+}
+
+// newLiveInformer builds and starts a SharedIndexInformer for gvr, blocking
+// until its initial list has populated the local store.
+func newLiveInformer(ctx context.Context, factory ClientFactory, gvr GVR, opts Options) (*liveInformer, error) {
+	cli, err := factory(ctx, gvr.Project)
+	if err != nil {
+		return nil, err
+	}
+	wc, ok := cli.(ctrlclient.WithWatch)
+	if !ok {
+		return nil, fmt.Errorf("client does not support watch")
+	}
+
+	listGVK := schema.GroupVersionKind{Group: gvr.Group, Version: gvr.Version, Kind: gvr.Kind + "List"}
+
+	listOptsFor := func(o metav1.ListOptions) []ctrlclient.ListOption {
+		raw := o
+		raw.LabelSelector = opts.LabelSelector
+		raw.FieldSelector = opts.FieldSelector
+		listOpts := []ctrlclient.ListOption{&ctrlclient.ListOptions{Raw: &raw}}
+		if gvr.Namespace != "" {
+			listOpts = append(listOpts, ctrlclient.InNamespace(gvr.Namespace))
+		}
+		return listOpts
+	}
+
+	// informerCtx, not the caller's request ctx, must back every list/watch
+	// call the informer issues over its lifetime: ctx is scoped to whichever
+	// Subscribe call happened to create the informer (a single MCP request),
+	// and gets canceled when that request ends, while the informer itself
+	// outlives it and is shared by later subscribers.
+	informerCtx, cancel := context.WithCancel(context.Background())
+
+	lw := &cache.ListWatch{
+		ListFunc: func(o metav1.ListOptions) (runtime.Object, error) {
+			var list unstructured.UnstructuredList
+			list.SetGroupVersionKind(listGVK)
+			if err := wc.List(informerCtx, &list, listOptsFor(o)...); err != nil {
+				return nil, err
+			}
+			return &list, nil
+		},
+		WatchFunc: func(o metav1.ListOptions) (watch.Interface, error) {
+			var list unstructured.UnstructuredList
+			list.SetGroupVersionKind(listGVK)
+			return wc.Watch(informerCtx, &list, listOptsFor(o)...)
+		},
+	}
+
+	resync := opts.ResyncPeriod
+	if resync <= 0 {
+		resync = defaultResyncPeriod
+	}
+
+	li := &liveInformer{
+		informer:    cache.NewSharedIndexInformer(lw, &unstructured.Unstructured{}, resync, cache.Indexers{}),
+		cancel:      cancel,
+		subscribers: make(map[int]chan api.WatchEvent),
+	}
+	li.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj any) { li.fanOut(api.WatchEventAdded, obj) },
+		UpdateFunc: func(_, obj any) { li.fanOut(api.WatchEventModified, obj) },
+		DeleteFunc: func(obj any) {
+			if tomb, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tomb.Obj
+			}
+			li.fanOut(api.WatchEventDeleted, obj)
+		},
+	})
+
+	go li.informer.Run(informerCtx.Done())
+	if !cache.WaitForCacheSync(informerCtx.Done(), li.informer.HasSynced) {
+		cancel()
+		return nil, fmt.Errorf("informer for %s/%s %s did not sync", gvr.Group, gvr.Version, gvr.Kind)
+	}
+	return li, nil
+}
+
+// fanOut applies drop-oldest backpressure per subscriber so one slow MCP
+// client can't stall the informer's event loop or the others.
+func (li *liveInformer) fanOut(t api.WatchEventType, obj any) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	ev := api.WatchEvent{Type: t, Object: u}
+	li.mu.Lock()
+	subs := make([]chan api.WatchEvent, 0, len(li.subscribers))
+	for _, s := range li.subscribers {
+		subs = append(subs, s)
+	}
+	li.mu.Unlock()
+	for _, s := range subs {
+		sendDroppingOldest(s, ev)
+	}
+}
+
+// list returns every object currently in the informer's local store.
+func (li *liveInformer) list() []*unstructured.Unstructured {
+	items := li.informer.GetStore().List()
+	out := make([]*unstructured.Unstructured, 0, len(items))
+	for _, it := range items {
+		if u, ok := it.(*unstructured.Unstructured); ok {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// sendDroppingOldest sends ev on out, dropping the oldest queued event
+// first if out is full, mirroring api's unexported helper of the same name.
+func sendDroppingOldest(out chan api.WatchEvent, ev api.WatchEvent) {
+	select {
+	case out <- ev:
+		return
+	default:
+	}
+	select {
+	case <-out:
+	default:
+	}
+	select {
+	case out <- ev:
+	default:
+	}
+}